@@ -0,0 +1,12 @@
+// Package v1alpha1 contains the generated Go bindings for admin.proto.
+//
+// The bindings (admin.pb.go, admin_grpc.pb.go, admin.pb.gw.go) are
+// generated, not hand-written; run `make generate` (buf generate) after
+// editing admin.proto and commit the regenerated files alongside it.
+//
+// internal/admin/runner and internal/admin/server, which consume these
+// bindings, are only built with the adminapi Go build tag, so the rest of
+// the control plane builds without them until they've been generated.
+package v1alpha1
+
+//go:generate buf generate
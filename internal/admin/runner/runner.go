@@ -0,0 +1,126 @@
+//go:build adminapi
+
+// Package runner starts the admin gRPC (and gRPC-gateway HTTP) service that
+// exposes the in-process message queues for debugging and introspection.
+//
+// Built only with the adminapi tag: it depends on the generated
+// api/admin/v1alpha1 bindings (admin.pb.go/admin_grpc.pb.go/admin.pb.gw.go),
+// which aren't committed yet (see api/admin/v1alpha1/doc.go), so it's kept
+// out of the default build until `make generate` has produced them.
+package runner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+
+	adminv1alpha1 "github.com/envoyproxy/gateway/api/admin/v1alpha1"
+	adminserver "github.com/envoyproxy/gateway/internal/admin/server"
+	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+	"github.com/envoyproxy/gateway/internal/message"
+)
+
+type Config struct {
+	config.Server
+	ProviderResources *message.ProviderResources
+	XdsIR             *message.XdsIR
+	InfraIR           *message.InfraIR
+	XdsResources      *message.XdsResources
+}
+
+type Runner struct {
+	Config
+
+	// bound is set once the gRPC listener is successfully bound, so Healthy
+	// can report the admin API isn't actually serving yet instead of always
+	// claiming success.
+	bound atomic.Bool
+}
+
+// New creates a new admin runner.
+func New(cfg *Config) *Runner {
+	return &Runner{Config: *cfg}
+}
+
+// Name implements runner.Runner.
+func (r *Runner) Name() string { return "admin" }
+
+// DependsOn implements runner.Runner: the admin API reads whatever the
+// other runners have already published, so it starts last.
+func (r *Runner) DependsOn() []string {
+	return []string{"provider", "gatewayapi", "infra", "xds-translator"}
+}
+
+// Healthy implements runner.Runner: healthy once the gRPC listener is bound.
+func (r *Runner) Healthy() error {
+	if !r.bound.Load() {
+		return errors.New("admin gRPC listener not yet bound")
+	}
+	return nil
+}
+
+// Start binds cfg.EnvoyGateway.Admin.Address and serves the admin gRPC API,
+// with reflection enabled, and binds cfg.EnvoyGateway.Admin.HTTPAddress and
+// serves the equivalent grpc-gateway HTTP API, until ctx is done.
+func (r *Runner) Start(ctx context.Context) error {
+	r.Logger = r.Logger.WithValues("runner", "admin")
+	defer r.bound.Store(false)
+
+	// Admin is read once at startup; RLock/RUnlock guards against the Config
+	// Manager's concurrent Lock-protected reload of the same shared
+	// *config.EnvoyGateway.
+	r.Server.EnvoyGateway.RLock()
+	addr := r.Server.EnvoyGateway.Admin.Address
+	httpAddr := r.Server.EnvoyGateway.Admin.HTTPAddress
+	r.Server.EnvoyGateway.RUnlock()
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	grpcServer := grpc.NewServer()
+	adminv1alpha1.RegisterAdminServiceServer(grpcServer, adminserver.New(r.Logger, r.ProviderResources, r.XdsIR, r.InfraIR, r.XdsResources))
+	reflection.Register(grpcServer)
+
+	httpServer, err := r.newHTTPGatewayServer(ctx, addr, httpAddr)
+	if err != nil {
+		return err
+	}
+	r.bound.Store(true)
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+		_ = httpServer.Close()
+	}()
+
+	go func() {
+		r.Logger.Info("started HTTP gateway", "address", httpAddr)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			r.Logger.Error(err, "HTTP gateway exited")
+		}
+	}()
+
+	r.Logger.Info("started", "address", addr)
+	return grpcServer.Serve(lis)
+}
+
+// newHTTPGatewayServer builds the grpc-gateway HTTP mux that translates the
+// google.api.http annotations on AdminService into plain HTTP/JSON, by
+// dialing the gRPC server at grpcAddr and serving on httpAddr.
+func (r *Runner) newHTTPGatewayServer(ctx context.Context, grpcAddr, httpAddr string) (*http.Server, error) {
+	mux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := adminv1alpha1.RegisterAdminServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, err
+	}
+	return &http.Server{Addr: httpAddr, Handler: mux}, nil
+}
@@ -0,0 +1,208 @@
+//go:build adminapi
+
+// Package server implements the admin gRPC service backed directly by the
+// in-process message queues, so a client can introspect a running
+// controller without kubectl exec or log scraping.
+//
+// Built only with the adminapi tag; see internal/admin/runner's package doc.
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-logr/logr"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	adminv1alpha1 "github.com/envoyproxy/gateway/api/admin/v1alpha1"
+	"github.com/envoyproxy/gateway/internal/message"
+)
+
+// snapshotTypeURLs are the resource types returned by GetXdsSnapshot, the
+// same four sub-dumps the troubleshoot runner cross-checks.
+var snapshotTypeURLs = []string{
+	resourcev3.ListenerType,
+	resourcev3.ClusterType,
+	resourcev3.RouteType,
+	resourcev3.EndpointType,
+}
+
+// Server implements adminv1alpha1.AdminServiceServer.
+type Server struct {
+	adminv1alpha1.UnimplementedAdminServiceServer
+
+	logger logr.Logger
+
+	ProviderResources *message.ProviderResources
+	XdsIR             *message.XdsIR
+	InfraIR           *message.InfraIR
+	XdsResources      *message.XdsResources
+}
+
+// New creates an admin Server backed by the given message queues.
+func New(logger logr.Logger, pResources *message.ProviderResources, xdsIR *message.XdsIR, infraIR *message.InfraIR, xResources *message.XdsResources) *Server {
+	return &Server{
+		logger:            logger.WithValues("runner", "admin"),
+		ProviderResources: pResources,
+		XdsIR:             xdsIR,
+		InfraIR:           infraIR,
+		XdsResources:      xResources,
+	}
+}
+
+// GetXdsIR dumps the current contents of the XdsIR watchable.
+func (s *Server) GetXdsIR(ctx context.Context, _ *adminv1alpha1.GetXdsIRRequest) (*adminv1alpha1.GetXdsIRResponse, error) {
+	resp := &adminv1alpha1.GetXdsIRResponse{Ir: make(map[string]*structpb.Struct)}
+	for _, kv := range s.XdsIR.LoadAll() {
+		st, err := toStruct(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		resp.Ir[kv.Key] = st
+	}
+	return resp, nil
+}
+
+// GetInfraIR dumps the current contents of the InfraIR watchable.
+func (s *Server) GetInfraIR(ctx context.Context, _ *adminv1alpha1.GetInfraIRRequest) (*adminv1alpha1.GetInfraIRResponse, error) {
+	resp := &adminv1alpha1.GetInfraIRResponse{Ir: make(map[string]*structpb.Struct)}
+	for _, kv := range s.InfraIR.LoadAll() {
+		st, err := toStruct(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		resp.Ir[kv.Key] = st
+	}
+	return resp, nil
+}
+
+// GetProviderResources dumps the current message.ProviderResources. Like
+// GetXdsIR/GetInfraIR, it has to read each of ProviderResources' watchables
+// through LoadAll rather than marshal the wrapper struct directly: a
+// Watchable carries its data behind unexported fields (a map plus
+// subscriber bookkeeping), so JSON-marshaling it as-is would just yield {}.
+func (s *Server) GetProviderResources(ctx context.Context, _ *adminv1alpha1.GetProviderResourcesRequest) (*adminv1alpha1.GetProviderResourcesResponse, error) {
+	gatewayClasses := make(map[string]interface{})
+	for _, kv := range s.ProviderResources.GatewayClasses.LoadAll() {
+		gatewayClasses[kv.Key] = kv.Value
+	}
+	gateways := make(map[string]interface{})
+	for _, kv := range s.ProviderResources.Gateways.LoadAll() {
+		gateways[kv.Key] = kv.Value
+	}
+	httpRoutes := make(map[string]interface{})
+	for _, kv := range s.ProviderResources.HTTPRoutes.LoadAll() {
+		httpRoutes[kv.Key] = kv.Value
+	}
+
+	st, err := toStruct(map[string]interface{}{
+		"gatewayClasses": gatewayClasses,
+		"gateways":       gateways,
+		"httpRoutes":     httpRoutes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &adminv1alpha1.GetProviderResourcesResponse{Resources: st}, nil
+}
+
+// GetXdsSnapshot dumps the most recently published xDS snapshot for the
+// given Envoy node ID.
+func (s *Server) GetXdsSnapshot(ctx context.Context, req *adminv1alpha1.GetXdsSnapshotRequest) (*adminv1alpha1.GetXdsSnapshotResponse, error) {
+	resp := &adminv1alpha1.GetXdsSnapshotResponse{}
+	found := false
+	for _, kv := range s.XdsResources.LoadAll() {
+		if kv.Key != req.GetNodeId() {
+			continue
+		}
+		found = true
+		for _, typeURL := range snapshotTypeURLs {
+			for _, res := range kv.Value.GetResources(typeURL) {
+				msg, ok := res.(proto.Message)
+				if !ok {
+					continue
+				}
+				any, err := anypb.New(msg)
+				if err != nil {
+					return nil, err
+				}
+				resp.Resources = append(resp.Resources, any)
+			}
+		}
+	}
+
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "no xDS snapshot published for node ID %q", req.GetNodeId())
+	}
+	return resp, nil
+}
+
+// Watch streams an event every time any of the message queues publishes an
+// update, filtered to the queues the caller asked for (all, if none given).
+func (s *Server) Watch(req *adminv1alpha1.WatchRequest, stream adminv1alpha1.AdminService_WatchServer) error {
+	ctx := stream.Context()
+	wantAll := len(req.Queues) == 0
+	want := make(map[adminv1alpha1.Queue]bool, len(req.Queues))
+	for _, q := range req.Queues {
+		want[q] = true
+	}
+
+	events := make(chan *adminv1alpha1.WatchResponse, 16)
+
+	if wantAll || want[adminv1alpha1.Queue_QUEUE_PROVIDER_RESOURCES] {
+		go forward(ctx, s.ProviderResources.GatewayClasses.Subscribe(ctx), adminv1alpha1.Queue_QUEUE_PROVIDER_RESOURCES, events)
+		go forward(ctx, s.ProviderResources.Gateways.Subscribe(ctx), adminv1alpha1.Queue_QUEUE_PROVIDER_RESOURCES, events)
+		go forward(ctx, s.ProviderResources.HTTPRoutes.Subscribe(ctx), adminv1alpha1.Queue_QUEUE_PROVIDER_RESOURCES, events)
+	}
+	if wantAll || want[adminv1alpha1.Queue_QUEUE_XDS_IR] {
+		go forward(ctx, s.XdsIR.Subscribe(ctx), adminv1alpha1.Queue_QUEUE_XDS_IR, events)
+	}
+	if wantAll || want[adminv1alpha1.Queue_QUEUE_INFRA_IR] {
+		go forward(ctx, s.InfraIR.Subscribe(ctx), adminv1alpha1.Queue_QUEUE_INFRA_IR, events)
+	}
+	if wantAll || want[adminv1alpha1.Queue_QUEUE_XDS_RESOURCES] {
+		go forward(ctx, s.XdsResources.Subscribe(ctx), adminv1alpha1.Queue_QUEUE_XDS_RESOURCES, events)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev := <-events:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// forward relays updates from a single watchable's subscription onto the
+// shared events channel, tagged with the queue they came from.
+func forward[T any](ctx context.Context, sub message.Subscription[string, T], q adminv1alpha1.Queue, events chan<- *adminv1alpha1.WatchResponse) {
+	message.HandleSubscription(sub, func(update message.Update[string, T]) {
+		select {
+		case events <- &adminv1alpha1.WatchResponse{Queue: q, Key: update.Key, Deleted: update.Delete}:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// toStruct marshals v into a google.protobuf.Struct for ad-hoc, schemaless
+// inspection over the admin API, round-tripping through JSON since v's
+// concrete IR types don't implement proto.Message themselves.
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
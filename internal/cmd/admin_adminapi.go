@@ -0,0 +1,28 @@
+//go:build adminapi
+
+package cmd
+
+import (
+	adminrunner "github.com/envoyproxy/gateway/internal/admin/runner"
+	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+	"github.com/envoyproxy/gateway/internal/message"
+	"github.com/envoyproxy/gateway/internal/runner"
+)
+
+// registerAdminRunner registers the admin gRPC runner, which exposes live
+// introspection of the provider resources, xDS IR, infra IR and xDS
+// snapshots, with supervisor.
+//
+// Built only with the adminapi tag: internal/admin/runner depends on the
+// generated api/admin/v1alpha1 bindings, which must be produced by `make
+// generate` before a binary built with this tag will compile.
+func registerAdminRunner(supervisor *runner.Supervisor, cfg *config.Server, pResources *message.ProviderResources, xdsIR *message.XdsIR, infraIR *message.InfraIR, xResources *message.XdsResources) {
+	adminRunner := adminrunner.New(&adminrunner.Config{
+		Server:            *cfg,
+		ProviderResources: pResources,
+		XdsIR:             xdsIR,
+		InfraIR:           infraIR,
+		XdsResources:      xResources,
+	})
+	supervisor.Register(adminRunner)
+}
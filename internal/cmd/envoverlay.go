@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// envPrefix is prepended to every generated environment variable name, e.g.
+// the Provider.Type field is overridden by ENVOY_GATEWAY_PROVIDER_TYPE.
+const envPrefix = "ENVOY_GATEWAY"
+
+// applyEnvOverrides overlays environment variables onto eg, following a
+// kelseyhightower/envconfig-style naming scheme: ENVOY_GATEWAY_<PATH>, where
+// PATH is the dot-separated struct field path with each segment
+// upper-cased and snake_cased, e.g. ENVOY_GATEWAY_GATEWAY_CONTROLLER_NAME
+// overrides Gateway.ControllerName.
+//
+// Precedence is: built-in defaults -> decoded YAML file -> environment
+// overrides, so this must run after config.NewDefaultServer and
+// config.Decode/SetDefaults have populated eg.
+func applyEnvOverrides(eg interface{}) error {
+	v := reflect.ValueOf(eg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("applyEnvOverrides: eg must be a non-nil pointer")
+	}
+	return overlayValue(v.Elem(), envPrefix)
+}
+
+// overlayValue recursively walks val, applying any matching environment
+// variables found under the given prefix.
+func overlayValue(val reflect.Value, prefix string) error {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			if !val.CanSet() || !hasEnvVarsUnder(prefix) {
+				return nil
+			}
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return overlayValue(val.Elem(), prefix)
+	case reflect.Struct:
+		t := val.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" { // unexported
+				continue
+			}
+			fieldPrefix := prefix + "_" + toEnvSegment(field.Name)
+			if err := overlayValue(val.Field(i), fieldPrefix); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		// Slices of strings are overridden as a single comma-separated value;
+		// slices of any other element type (structs, or non-string scalars)
+		// are left to the decoded file.
+		if raw, ok := os.LookupEnv(prefix); ok && val.Type().Elem().Kind() == reflect.String {
+			parts := strings.Split(raw, ",")
+			items := reflect.MakeSlice(val.Type(), len(parts), len(parts))
+			for i, p := range parts {
+				items.Index(i).SetString(strings.TrimSpace(p))
+			}
+			val.Set(items)
+		}
+		return nil
+	default:
+		raw, ok := os.LookupEnv(prefix)
+		if !ok {
+			return nil
+		}
+		return setScalar(val, raw)
+	}
+}
+
+// hasEnvVarsUnder reports whether any environment variable is set that would
+// be consumed by prefix itself or by a descendant field of it, e.g. with
+// prefix "ENVOY_GATEWAY_PROVIDER" it matches both ENVOY_GATEWAY_PROVIDER and
+// ENVOY_GATEWAY_PROVIDER_TYPE. Used to decide whether a nil pointer field is
+// worth allocating so overlayValue can recurse into it.
+func hasEnvVarsUnder(prefix string) bool {
+	for _, kv := range os.Environ() {
+		key := kv[:strings.IndexByte(kv, '=')]
+		if key == prefix || strings.HasPrefix(key, prefix+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// setScalar sets val, which must be a scalar kind, to the parsed contents of
+// raw. Only string, bool and signed integer kinds are set; any other kind
+// (unsigned integers, floats) is left unchanged.
+func setScalar(val reflect.Value, raw string) error {
+	if !val.CanSet() {
+		return nil
+	}
+	switch val.Kind() {
+	case reflect.String:
+		val.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid bool value %q: %w", raw, err)
+		}
+		val.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid int value %q: %w", raw, err)
+		}
+		val.SetInt(n)
+	}
+	return nil
+}
+
+// toEnvSegment converts a Go exported field name such as "ControllerName"
+// into its SCREAMING_SNAKE_CASE environment variable segment
+// "CONTROLLER_NAME". Runs of capitals are treated as a single unit, so an
+// initialism like "XDS" in "XDSEnableV2Compat" becomes "XDS_ENABLE_V2_COMPAT"
+// rather than "X_D_S_ENABLE_V2_COMPAT".
+func toEnvSegment(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		isUpper := r >= 'A' && r <= 'Z'
+		if i > 0 && isUpper {
+			prevUpper := runes[i-1] >= 'A' && runes[i-1] <= 'Z'
+			// Start of a new word: previous rune was lowercase/digit, e.g.
+			// the "N" in "ControllerName" or the "L" in "ClusterLoadAssignment".
+			newWord := !prevUpper
+			// End of an acronym run followed by a new word, e.g. the "E"
+			// after "XDS" in "XDSEnable" (XDS + Enable).
+			endOfAcronym := prevUpper && i+1 < len(runes) && runes[i+1] >= 'a' && runes[i+1] <= 'z'
+			if newWord || endOfAcronym {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestToEnvSegment(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "single word", in: "Type", want: "TYPE"},
+		{name: "two words", in: "ControllerName", want: "CONTROLLER_NAME"},
+		{name: "leading acronym", in: "XDS", want: "XDS"},
+		{name: "acronym then word", in: "XDSEnableV2Compat", want: "XDS_ENABLE_V2_COMPAT"},
+		{name: "word then acronym", in: "EnableXDS", want: "ENABLE_XDS"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := toEnvSegment(c.in); got != c.want {
+				t.Errorf("toEnvSegment(%q) = %q, want %q", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	type Gateway struct {
+		ControllerName string
+	}
+	type EnvoyGateway struct {
+		Gateway *Gateway
+	}
+
+	t.Setenv("ENVOY_GATEWAY_GATEWAY_CONTROLLER_NAME", "gateway.envoyproxy.io/overridden")
+
+	eg := &EnvoyGateway{Gateway: &Gateway{ControllerName: "gateway.envoyproxy.io/gatewayclass-controller"}}
+	if err := applyEnvOverrides(eg); err != nil {
+		t.Fatalf("applyEnvOverrides returned an error: %v", err)
+	}
+
+	if got, want := eg.Gateway.ControllerName, "gateway.envoyproxy.io/overridden"; got != want {
+		t.Errorf("ControllerName = %q, want %q", got, want)
+	}
+}
+
+func TestApplyEnvOverridesRequiresPointer(t *testing.T) {
+	if err := applyEnvOverrides(struct{}{}); err == nil {
+		t.Fatal("expected an error for a non-pointer argument, got nil")
+	}
+}
+
+func TestApplyEnvOverridesAllocatesNilPointer(t *testing.T) {
+	type Provider struct {
+		Type string
+	}
+	type EnvoyGateway struct {
+		Provider *Provider
+	}
+
+	t.Setenv("ENVOY_GATEWAY_PROVIDER_TYPE", "kubernetes")
+
+	eg := &EnvoyGateway{} // Provider left nil, as it is when no config file is decoded.
+	if err := applyEnvOverrides(eg); err != nil {
+		t.Fatalf("applyEnvOverrides returned an error: %v", err)
+	}
+
+	if eg.Provider == nil {
+		t.Fatal("Provider = nil, want it allocated so the env override could apply")
+	}
+	if got, want := eg.Provider.Type, "kubernetes"; got != want {
+		t.Errorf("Provider.Type = %q, want %q", got, want)
+	}
+}
+
+func TestApplyEnvOverridesLeavesUnmatchedNilPointer(t *testing.T) {
+	type Provider struct {
+		Type string
+	}
+	type EnvoyGateway struct {
+		Provider *Provider
+	}
+
+	eg := &EnvoyGateway{}
+	if err := applyEnvOverrides(eg); err != nil {
+		t.Fatalf("applyEnvOverrides returned an error: %v", err)
+	}
+
+	if eg.Provider != nil {
+		t.Errorf("Provider = %+v, want nil since no ENVOY_GATEWAY_PROVIDER* env vars were set", eg.Provider)
+	}
+}
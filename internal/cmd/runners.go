@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/envoyproxy/gateway/internal/runner"
+)
+
+// adaptedRunner wraps one of the existing fire-and-forget runners (whose
+// Start spawns a goroutine and returns immediately) so it satisfies
+// runner.Runner: Start blocks until ctx is done, which is what the
+// Supervisor expects in order to detect an early exit and restart it.
+type adaptedRunner struct {
+	name    string
+	deps    []string
+	startFn func(ctx context.Context) error
+
+	// started is set once startFn has returned without error, i.e. the
+	// wrapped runner's own setup succeeded. The wrapped runners don't expose
+	// a liveness signal of their own (e.g. "last fetch succeeded"), so this
+	// is the most this adapter can honestly report: not started yet, vs.
+	// past its own startup.
+	started atomic.Bool
+}
+
+func (a *adaptedRunner) Name() string {
+	return a.name
+}
+
+func (a *adaptedRunner) DependsOn() []string {
+	return a.deps
+}
+
+// Healthy reports whether the wrapped runner's startFn has returned
+// successfully. This can't detect the wrapped runner's background goroutine
+// dying after startup, since none of them currently expose that; Start
+// blocking on ctx.Done (rather than returning immediately) is what lets the
+// Supervisor at least catch that case as an early, unexpected exit.
+func (a *adaptedRunner) Healthy() error {
+	if !a.started.Load() {
+		return errors.New("wrapped runner has not finished starting yet")
+	}
+	return nil
+}
+
+// Start starts the wrapped runner, then blocks until ctx is done so the
+// Supervisor can detect (and restart) an early, unexpected exit.
+func (a *adaptedRunner) Start(ctx context.Context) error {
+	defer a.started.Store(false)
+	if err := a.startFn(ctx); err != nil {
+		return err
+	}
+	a.started.Store(true)
+	<-ctx.Done()
+	return nil
+}
@@ -1,14 +1,23 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
+	"github.com/envoyproxy/gateway/internal/configmanager"
 	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+	ratelimitrunner "github.com/envoyproxy/gateway/internal/globalratelimit/runner"
 	gatewayapirunner "github.com/envoyproxy/gateway/internal/gatewayapi/runner"
 	infrarunner "github.com/envoyproxy/gateway/internal/infrastructure/runner"
 	"github.com/envoyproxy/gateway/internal/message"
 	providerrunner "github.com/envoyproxy/gateway/internal/provider/runner"
+	"github.com/envoyproxy/gateway/internal/runner"
+	"github.com/envoyproxy/gateway/internal/troubleshoot"
+	troubleshootrunner "github.com/envoyproxy/gateway/internal/troubleshoot/runner"
 	xdsserverrunner "github.com/envoyproxy/gateway/internal/xds/server/runner"
 	xdstranslatorrunner "github.com/envoyproxy/gateway/internal/xds/translator/runner"
 )
@@ -16,6 +25,10 @@ import (
 var (
 	// cfgPath is the path to the EnvoyGateway configuration file.
 	cfgPath string
+	// printConfig, when set, dumps the fully-resolved config (defaults +
+	// decoded file + environment overrides) to stdout and exits without
+	// starting any runners.
+	printConfig bool
 )
 
 // getServerCommand returns the server cobra command to be executed.
@@ -30,6 +43,8 @@ func getServerCommand() *cobra.Command {
 	}
 	cmd.PersistentFlags().StringVarP(&cfgPath, "config-path", "c", "",
 		"The path to the configuration file.")
+	cmd.PersistentFlags().BoolVar(&printConfig, "print-config", false,
+		"Print the fully-resolved configuration, including environment overrides, and exit.")
 
 	return cmd
 }
@@ -41,6 +56,15 @@ func server() error {
 		return err
 	}
 
+	if printConfig {
+		out, err := yaml.Marshal(cfg.EnvoyGateway)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
 	if err := setupRunners(cfg); err != nil {
 		return err
 	}
@@ -72,80 +96,149 @@ func getConfig() (*config.Server, error) {
 		eg.SetDefaults()
 		cfg.EnvoyGateway = eg
 	}
+
+	// Environment variables take precedence over the decoded file, which in
+	// turn takes precedence over the built-in defaults.
+	if err := applyEnvOverrides(cfg.EnvoyGateway); err != nil {
+		log.Error(err, "failed to apply environment variable overrides")
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
-// setupRunners starts all the runners required for the Envoy Gateway to
-// fulfill its tasks.
+// setupRunners registers all the runners required for the Envoy Gateway to
+// fulfill its tasks with a Supervisor, which starts them in dependency
+// order, restarts any that exit unexpectedly, aggregates their health into
+// /healthz and /readyz, and shuts them down in reverse dependency order.
 func setupRunners(cfg *config.Server) error {
-	// TODO - Setup a Config Manager
-	// https://github.com/envoyproxy/gateway/issues/43
 	ctx := ctrl.SetupSignalHandler()
 
+	// healthAddr is the bind address for the supervisor's aggregate
+	// /healthz and /readyz endpoints.
+	const healthAddr = ":19001"
+	supervisor := runner.NewSupervisor(cfg.Logger, healthAddr)
+
+	// The Config Manager watches the config file for changes and
+	// hot-reloads cfg.EnvoyGateway in place, so every runner below observes
+	// the fields it cares about without a restart.
+	cfgManager := configmanager.New(cfgPath, cfg.EnvoyGateway, cfg.Logger)
+	supervisor.Register(cfgManager)
+	go message.HandleSubscription(cfgManager.Config.Subscribe(ctx), func(update message.Update[string, *config.EnvoyGateway]) {
+		cfg.Logger.Info("applied a config reload", "key", update.Key)
+	})
+
 	pResources := new(message.ProviderResources)
-	// Start the Provider Service
-	// It fetches the resources from the configured provider type
-	// and publishes it
+	// The Provider Service fetches the resources from the configured
+	// provider type and publishes it.
 	providerRunner := providerrunner.New(&providerrunner.Config{
 		Server:            *cfg,
 		ProviderResources: pResources,
 	})
-	if err := providerRunner.Start(ctx); err != nil {
-		return err
-	}
+	supervisor.Register(&adaptedRunner{
+		name:    "provider",
+		startFn: providerRunner.Start,
+	})
 
 	xdsIR := new(message.XdsIR)
 	infraIR := new(message.InfraIR)
-	// Start the GatewayAPI Translator Runner
-	// It subscribes to the provider resources, translates it to xDS IR
-	// and infra IR resources and publishes them.
+	// The GatewayAPI Translator Runner subscribes to the provider resources,
+	// translates it to xDS IR and infra IR resources and publishes them.
 	gwRunner := gatewayapirunner.New(&gatewayapirunner.Config{
 		Server:            *cfg,
 		ProviderResources: pResources,
 		XdsIR:             xdsIR,
 		InfraIR:           infraIR,
 	})
-	if err := gwRunner.Start(ctx); err != nil {
-		return err
-	}
+	supervisor.Register(&adaptedRunner{
+		name:    "gatewayapi",
+		deps:    []string{"provider"},
+		startFn: gwRunner.Start,
+	})
 
 	xResources := new(message.XdsResources)
-	// Start the Xds Translator Service
-	// It subscribes to the xdsIR, translates it into xds Resources and publishes it.
+	// The Xds Translator Service subscribes to the xdsIR, translates it into
+	// xds Resources and publishes it.
 	xdsTranslatorRunner := xdstranslatorrunner.New(&xdstranslatorrunner.Config{
 		Server:       *cfg,
 		XdsIR:        xdsIR,
 		XdsResources: xResources,
 	})
-	if err := xdsTranslatorRunner.Start(ctx); err != nil {
-		return err
-	}
+	supervisor.Register(&adaptedRunner{
+		name:    "xds-translator",
+		deps:    []string{"gatewayapi"},
+		startFn: xdsTranslatorRunner.Start,
+	})
 
-	// Start the Infra Manager Runner
-	// It subscribes to the infraIR, translates it into Envoy Proxy infrastructure
-	// resources such as K8s deployment and services.
+	// The Infra Manager Runner subscribes to the infraIR, translates it into
+	// Envoy Proxy infrastructure resources such as K8s deployment and
+	// services.
 	infraRunner := infrarunner.New(&infrarunner.Config{
 		Server:  *cfg,
 		InfraIR: infraIR,
 	})
-	if err := infraRunner.Start(ctx); err != nil {
-		return err
-	}
+	supervisor.Register(&adaptedRunner{
+		name:    "infra",
+		deps:    []string{"gatewayapi"},
+		startFn: infraRunner.Start,
+	})
 
-	// Start the xDS Server
-	// It subscribes to the xds Resources and configures the remote Envoy Proxy
-	// via the xDS Protocol
+	// The xDS Server subscribes to the xds Resources and configures the
+	// remote Envoy Proxy via the xDS Protocol.
 	xdsServerRunner := xdsserverrunner.New(&xdsserverrunner.Config{
 		Server:       *cfg,
 		XdsResources: xResources,
 	})
-	if err := xdsServerRunner.Start(ctx); err != nil {
+	supervisor.Register(xdsServerRunner)
+
+	if cfg.EnvoyGateway.RateLimit != nil {
+		// The Global RateLimit Runner subscribes to the xdsIR, and when a
+		// gateway has rate limit descriptors configured, publishes the infra
+		// resources required to run the envoy ratelimit service.
+		rateLimitRunner := ratelimitrunner.New(&ratelimitrunner.Config{
+			Server:  *cfg,
+			XdsIR:   xdsIR,
+			InfraIR: infraIR,
+		})
+		supervisor.Register(&adaptedRunner{
+			name:    "global-ratelimit",
+			deps:    []string{"gatewayapi", "infra"},
+			startFn: rateLimitRunner.Start,
+		})
+	}
+
+	if cfg.EnvoyGateway.Troubleshoot != nil {
+		// The Troubleshoot Runner periodically diffs the config_dump served
+		// by the managed Envoy proxies against the most recently published
+		// XdsResources, surfacing any discrepancies as log events.
+		k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{})
+		if err != nil {
+			return err
+		}
+		troubleshootRunner := troubleshootrunner.New(&troubleshootrunner.Config{
+			Server:       *cfg,
+			XdsResources: xResources,
+			Fetcher: &troubleshoot.PodAdminFetcher{
+				Client:   k8sClient,
+				Selector: client.MatchingLabels{"control-plane": "envoy-proxy"},
+			},
+			Client: k8sClient,
+		})
+		supervisor.Register(troubleshootRunner)
+	}
+
+	// The admin runner exposes a gRPC admin API for live introspection of
+	// the provider resources, xDS IR, infra IR and xDS snapshots. Only
+	// registered when built with the adminapi tag; see registerAdminRunner.
+	registerAdminRunner(supervisor, cfg, pResources, xdsIR, infraIR, xResources)
+
+	// Start blocks until ctx is done, then shuts every runner down in
+	// reverse dependency order.
+	if err := supervisor.Start(ctx); err != nil {
 		return err
 	}
 
-	// Wait until done
-	<-ctx.Done()
-	// Close messages
+	// Close messages now that every runner has stopped consuming them.
 	pResources.GatewayClasses.Close()
 	pResources.Gateways.Close()
 	pResources.HTTPRoutes.Close()
@@ -0,0 +1,232 @@
+// Package configmanager implements a Config Manager that watches the
+// EnvoyGateway configuration file on disk and hot-reloads it without
+// requiring a process restart.
+//
+// See https://github.com/envoyproxy/gateway/issues/43.
+package configmanager
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+
+	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+	"github.com/envoyproxy/gateway/internal/message"
+)
+
+// debounceInterval coalesces bursts of filesystem events (e.g. editors that
+// write a file via rename-and-replace) into a single reload.
+const debounceInterval = 500 * time.Millisecond
+
+// Manager watches cfgPath for changes, decodes/validates the new file, and
+// publishes the result on Config so interested runners can react to the
+// fields they care about without a restart.
+//
+// Manager implements runner.Runner so it is started, health-checked and
+// shut down by the Supervisor like every other runner.
+type Manager struct {
+	cfgPath string
+	logger  logr.Logger
+
+	// current is the shared *config.EnvoyGateway every runner was handed at
+	// startup. reload mutates its fields in place, rather than swapping in a
+	// new pointer only the Manager itself would see, so every runner holding
+	// it observes the change without a restart.
+	current *config.EnvoyGateway
+
+	// Config is the watchable published whenever a new, valid configuration
+	// is loaded.
+	Config *message.Config
+
+	// lastReloadErrMu guards lastReloadErr, which Healthy reports: non-nil
+	// means the most recent reload attempt failed to decode or validate and
+	// the Manager rolled back to the last-known-good config instead.
+	lastReloadErrMu sync.RWMutex
+	lastReloadErr   error
+}
+
+// New creates a Config Manager seeded with the initial configuration.
+func New(cfgPath string, initial *config.EnvoyGateway, logger logr.Logger) *Manager {
+	return &Manager{
+		cfgPath: cfgPath,
+		logger:  logger.WithValues("runner", "config-manager"),
+		current: initial,
+		Config:  new(message.Config),
+	}
+}
+
+// Name implements runner.Runner.
+func (m *Manager) Name() string { return "config-manager" }
+
+// DependsOn implements runner.Runner: the Manager only mutates the shared
+// config struct every other runner was constructed with, so it has no
+// dependencies of its own.
+func (m *Manager) DependsOn() []string { return nil }
+
+// Healthy implements runner.Runner: unhealthy when the most recent reload
+// attempt failed, since that means the config file on disk has drifted from
+// what the running controller actually has loaded.
+func (m *Manager) Healthy() error {
+	m.lastReloadErrMu.RLock()
+	defer m.lastReloadErrMu.RUnlock()
+	return m.lastReloadErr
+}
+
+// Start watches cfgPath for changes, blocking until ctx is done. When
+// cfgPath is unset there is nothing to watch, so it just blocks.
+func (m *Manager) Start(ctx context.Context) error {
+	if m.cfgPath == "" {
+		// Nothing to watch when running with default parameters.
+		<-ctx.Done()
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// Watch cfgPath's parent directory, not cfgPath itself: Kubernetes
+	// mounts a ConfigMap as a symlink to a versioned directory and updates
+	// it by atomically retargeting the symlink, which detaches inotify
+	// from the old target and leaves a watch on cfgPath alone silently
+	// dead after the first update. Watching the directory, and re-adding
+	// cfgPath on the rename/remove that swap produces, survives that.
+	watchDir := filepath.Dir(m.cfgPath)
+	if err := watcher.Add(watchDir); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	m.logger.Info("watching config file for changes", "path", m.cfgPath)
+	m.watch(ctx, watcher)
+	return nil
+}
+
+func (m *Manager) watch(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	var debounce *time.Timer
+	reload := make(chan struct{}, 1)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(m.cfgPath) {
+				continue
+			}
+			if event.Op&(fsnotify.Rename|fsnotify.Remove) != 0 {
+				// The ConfigMap symlink was just retargeted; re-arm the
+				// watch on the new target so future updates keep firing.
+				if err := watcher.Add(m.cfgPath); err != nil {
+					m.logger.Error(err, "failed to re-watch config file after rename/remove", "path", m.cfgPath)
+				}
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.AfterFunc(debounceInterval, func() { reload <- struct{}{} })
+			} else {
+				debounce.Reset(debounceInterval)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			m.logger.Error(err, "watcher error")
+		case <-reload:
+			m.reload()
+		}
+	}
+}
+
+// reload decodes and validates the config file, rolling back to the
+// last-known-good configuration if it's invalid, and publishing the new
+// configuration otherwise.
+func (m *Manager) reload() {
+	eg, err := config.Decode(m.cfgPath)
+	if err != nil {
+		m.logger.Error(err, "failed to decode config file, keeping last-known-good config", "name", m.cfgPath)
+		m.setLastReloadErr(err)
+		return
+	}
+	eg.SetDefaults()
+	if err := eg.Validate(); err != nil {
+		m.logger.Error(err, "invalid config file, keeping last-known-good config", "name", m.cfgPath)
+		m.setLastReloadErr(err)
+		return
+	}
+
+	restartRequired := changedRestartRequiredFields(m.current, eg)
+	if len(restartRequired) > 0 {
+		m.logger.Info("config changes require a restart to take effect, ignoring", "fields", restartRequired)
+	}
+
+	// Mutate the shared config struct in place, field by field: every
+	// runner was constructed holding this same *config.EnvoyGateway
+	// pointer, so this is how they observe the reload without a restart.
+	// This must go through m.current's own lock (and not a plain `*m.current
+	// = *eg`, which both races any concurrent RLock-ing reader and would
+	// copy m.current's mutex by value) so readers see a consistent config.
+	m.current.Lock()
+	if !contains(restartRequired, "provider.type") {
+		m.current.Provider = eg.Provider
+	}
+	if !contains(restartRequired, "gateway.controllerName") {
+		m.current.Gateway = eg.Gateway
+	}
+	m.current.Logging = eg.Logging
+	m.current.Admin = eg.Admin
+	m.current.RateLimit = eg.RateLimit
+	m.current.Troubleshoot = eg.Troubleshoot
+	m.current.XDS = eg.XDS
+	m.current.Unlock()
+
+	m.Config.Store(message.ConfigKey, m.current)
+	m.logger.Info("reloaded config file", "name", m.cfgPath)
+	m.setLastReloadErr(nil)
+}
+
+// setLastReloadErr records the outcome of the most recent reload attempt for
+// Healthy to report.
+func (m *Manager) setLastReloadErr(err error) {
+	m.lastReloadErrMu.Lock()
+	m.lastReloadErr = err
+	m.lastReloadErrMu.Unlock()
+}
+
+// changedRestartRequiredFields returns which of the restart-required fields
+// (provider.type, gateway.controllerName) differ between the old and new
+// config.
+func changedRestartRequiredFields(old, new *config.EnvoyGateway) []string {
+	var changed []string
+	if old == nil {
+		return changed
+	}
+	if old.Provider != nil && new.Provider != nil && old.Provider.Type != new.Provider.Type {
+		changed = append(changed, "provider.type")
+	}
+	if old.Gateway != nil && new.Gateway != nil && old.Gateway.ControllerName != new.Gateway.ControllerName {
+		changed = append(changed, "gateway.controllerName")
+	}
+	return changed
+}
+
+// contains reports whether s contains v.
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package configmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+)
+
+func TestReloadMutatesSharedConfigInPlace(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("logging:\n  level: debug\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	shared := &config.EnvoyGateway{Logging: &config.Logging{Level: "info"}}
+	m := New(cfgPath, shared, logr.Discard())
+
+	m.reload()
+
+	if shared.Logging.Level != "debug" {
+		t.Errorf("shared config was not mutated in place: Logging.Level = %q, want %q", shared.Logging.Level, "debug")
+	}
+}
+
+func TestReloadKeepsLastKnownGoodOnInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	// rateLimit with no backend fails Validate.
+	if err := os.WriteFile(cfgPath, []byte("rateLimit: {}\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	shared := &config.EnvoyGateway{Logging: &config.Logging{Level: "info"}}
+	m := New(cfgPath, shared, logr.Discard())
+
+	m.reload()
+
+	if shared.Logging.Level != "info" {
+		t.Errorf("expected the last-known-good config to be kept, got Logging.Level = %q", shared.Logging.Level)
+	}
+}
+
+func TestReloadIgnoresRestartRequiredFieldChanges(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte(
+		"provider:\n  type: kubernetes\ngateway:\n  controllerName: gateway.envoyproxy.io/other-controller\nlogging:\n  level: debug\n",
+	), 0o644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	shared := &config.EnvoyGateway{
+		Provider: &config.Provider{Type: "file"},
+		Gateway:  &config.Gateway{ControllerName: "gateway.envoyproxy.io/gatewayclass-controller"},
+		Logging:  &config.Logging{Level: "info"},
+	}
+	m := New(cfgPath, shared, logr.Discard())
+
+	m.reload()
+
+	if shared.Provider.Type != "file" {
+		t.Errorf("Provider.Type = %q, want it left at %q since changing it requires a restart", shared.Provider.Type, "file")
+	}
+	if shared.Gateway.ControllerName != "gateway.envoyproxy.io/gatewayclass-controller" {
+		t.Errorf("Gateway.ControllerName = %q, want it left unchanged since changing it requires a restart", shared.Gateway.ControllerName)
+	}
+	if shared.Logging.Level != "debug" {
+		t.Errorf("Logging.Level = %q, want %q to still apply live", shared.Logging.Level, "debug")
+	}
+}
+
+func TestReloadIsRaceFreeAgainstConcurrentReaders(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("logging:\n  level: debug\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	shared := &config.EnvoyGateway{Logging: &config.Logging{Level: "info"}}
+	m := New(cfgPath, shared, logr.Discard())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			shared.RLock()
+			_ = shared.Logging.Level
+			shared.RUnlock()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		m.reload()
+	}
+	<-done
+}
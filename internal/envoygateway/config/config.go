@@ -0,0 +1,156 @@
+// Package config defines the control plane's own configuration: the
+// EnvoyGateway stanza loaded from the config file (or defaulted/overridden),
+// and the Server wrapper that carries it alongside a logger for the
+// lifetime of the process.
+//
+// This file carries the subset of the schema referenced by the runners
+// added in this series (Admin, Provider, Gateway, Logging, RateLimit,
+// Troubleshoot, XDS); the rest of the schema is assumed to already exist
+// upstream.
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/yaml"
+)
+
+// Server wraps the resolved EnvoyGateway configuration with a logger, and is
+// threaded into every runner.
+type Server struct {
+	// EnvoyGateway is the resolved configuration: built-in defaults,
+	// overlaid with the decoded config file, overlaid with environment
+	// variable overrides.
+	EnvoyGateway *EnvoyGateway
+	// Logger is passed to every runner. NewDefaultServer sets it to
+	// logr.Discard(); it is not built from EnvoyGateway.Logging.
+	Logger logr.Logger
+}
+
+// EnvoyGateway is the top-level configuration for the Envoy Gateway control
+// plane.
+//
+// The Config Manager hot-reloads a config file by mutating a single shared
+// *EnvoyGateway's fields in place, so every runner handed that same pointer
+// at startup observes the change without a restart. Because of that, reads
+// and the reload's write race unless both go through mu: callers that read
+// fields after startup should RLock/RUnlock; the Config Manager Locks/
+// Unlocks around applying a reload.
+type EnvoyGateway struct {
+	mu sync.RWMutex
+
+	Provider *Provider `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Gateway  *Gateway  `json:"gateway,omitempty" yaml:"gateway,omitempty"`
+	Logging  *Logging  `json:"logging,omitempty" yaml:"logging,omitempty"`
+	Admin    *Admin    `json:"admin,omitempty" yaml:"admin,omitempty"`
+
+	// RateLimit enables the global rate-limit runner and the ratelimit
+	// service deployment it manages. Nil disables rate limiting entirely.
+	RateLimit *RateLimit `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	// Troubleshoot enables the troubleshoot runner that cross-checks
+	// translated xDS against the live Envoy config_dump. Nil disables it.
+	Troubleshoot *Troubleshoot `json:"troubleshoot,omitempty" yaml:"troubleshoot,omitempty"`
+	// XDS configures the xDS server.
+	XDS *XDS `json:"xds,omitempty" yaml:"xds,omitempty"`
+}
+
+// Provider configures the resource provider (e.g. Kubernetes, File).
+type Provider struct {
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// Gateway configures the GatewayClass controller this instance reconciles.
+type Gateway struct {
+	ControllerName string `json:"controllerName,omitempty" yaml:"controllerName,omitempty"`
+}
+
+// Logging configures the control plane's own logging.
+type Logging struct {
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+}
+
+// Admin configures the control plane's admin gRPC/HTTP API.
+type Admin struct {
+	// Address is the bind address for the admin gRPC API.
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	// HTTPAddress is the bind address for the grpc-gateway HTTP mux that
+	// fronts the admin gRPC API, serving the google.api.http annotations
+	// declared on AdminService.
+	HTTPAddress string `json:"httpAddress,omitempty" yaml:"httpAddress,omitempty"`
+}
+
+// Troubleshoot configures the troubleshoot runner.
+type Troubleshoot struct{}
+
+// XDS configures the xDS server.
+type XDS struct {
+	// EnableV2Compat registers v2-compatible Listener, Cluster, Route and
+	// Endpoint discovery services alongside the v3 services, for legacy
+	// sidecars that request v2 type URLs. No v2 aggregated (ADS) discovery
+	// service is registered. Defaults to false.
+	EnableV2Compat bool `json:"enableV2Compat,omitempty" yaml:"enableV2Compat,omitempty"`
+}
+
+// RLock/RUnlock synchronize a read of e's fields against a concurrent
+// reload applied through Lock/Unlock.
+func (e *EnvoyGateway) RLock()   { e.mu.RLock() }
+func (e *EnvoyGateway) RUnlock() { e.mu.RUnlock() }
+
+// Lock/Unlock synchronize a reload's in-place mutation of e's fields
+// against concurrent readers using RLock/RUnlock.
+func (e *EnvoyGateway) Lock()   { e.mu.Lock() }
+func (e *EnvoyGateway) Unlock() { e.mu.Unlock() }
+
+// NewDefaultServer returns a Server populated with built-in defaults.
+func NewDefaultServer() (*Server, error) {
+	return &Server{
+		EnvoyGateway: defaultEnvoyGateway(),
+		Logger:       logr.Discard(),
+	}, nil
+}
+
+func defaultEnvoyGateway() *EnvoyGateway {
+	return &EnvoyGateway{
+		Gateway: &Gateway{ControllerName: "gateway.envoyproxy.io/gatewayclass-controller"},
+		Logging: &Logging{Level: "info"},
+		Admin:   &Admin{Address: ":19000", HTTPAddress: ":19002"},
+	}
+}
+
+// Decode reads and unmarshals the EnvoyGateway config file at path.
+func Decode(path string) (*EnvoyGateway, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
+	}
+	eg := new(EnvoyGateway)
+	if err := yaml.Unmarshal(data, eg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config file %q: %w", path, err)
+	}
+	return eg, nil
+}
+
+// SetDefaults fills in any unset fields of eg with their default values.
+func (e *EnvoyGateway) SetDefaults() {
+	defaults := defaultEnvoyGateway()
+	if e.Gateway == nil {
+		e.Gateway = defaults.Gateway
+	}
+	if e.Logging == nil {
+		e.Logging = defaults.Logging
+	}
+	if e.Admin == nil {
+		e.Admin = defaults.Admin
+	}
+}
+
+// Validate checks eg for internal consistency.
+func (e *EnvoyGateway) Validate() error {
+	if e.RateLimit != nil && e.RateLimit.Backend.Redis == nil {
+		return fmt.Errorf("rateLimit.backend.redis is required when rateLimit is set")
+	}
+	return nil
+}
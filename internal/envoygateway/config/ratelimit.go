@@ -0,0 +1,24 @@
+package config
+
+// RateLimit configures the global rate-limit runner and the ratelimit
+// service it deploys. A nil RateLimit on EnvoyGateway means rate limiting
+// is disabled and the runner is never started.
+type RateLimit struct {
+	// Backend selects the store used by the ratelimit service to track
+	// request counts. Redis is the only backend currently supported.
+	Backend RateLimitBackend `json:"backend,omitempty" yaml:"backend,omitempty"`
+}
+
+// RateLimitBackend configures the ratelimit service's storage backend.
+type RateLimitBackend struct {
+	// Redis configures the Redis instance the ratelimit service connects
+	// to. Required since Redis is the only supported backend.
+	Redis *RateLimitRedisSettings `json:"redis,omitempty" yaml:"redis,omitempty"`
+}
+
+// RateLimitRedisSettings configures the Redis connection used by the
+// ratelimit service.
+type RateLimitRedisSettings struct {
+	// URL is the Redis connection string, e.g. "redis.svc.cluster.local:6379".
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+}
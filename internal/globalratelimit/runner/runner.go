@@ -0,0 +1,164 @@
+// Package runner implements the global rate limit runner, which is
+// responsible for translating rate limit IR into the configuration consumed
+// by the Envoy Proxy ratelimit service and for ensuring that service is
+// deployed by the infrastructure runner.
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+	"github.com/envoyproxy/gateway/internal/ir"
+	"github.com/envoyproxy/gateway/internal/message"
+)
+
+const (
+	// infraResourceName is the name given to the ratelimit service's
+	// Deployment, Service and ConfigMap.
+	infraResourceName = "envoy-ratelimit"
+	// rateLimitServiceImage is the upstream ratelimit service image the
+	// infra runner deploys.
+	rateLimitServiceImage = "envoyproxy/ratelimit:master"
+	// rateLimitGRPCPort is the port the ratelimit service's gRPC API
+	// listens on, matching the cluster the xDS translator's ratelimit HTTP
+	// filter points at.
+	rateLimitGRPCPort = 8081
+	// rateLimitConfigFile is the name of the descriptor config file mounted
+	// into the ratelimit service container.
+	rateLimitConfigFile = "config.yaml"
+)
+
+type Config struct {
+	config.Server
+	XdsIR   *message.XdsIR
+	InfraIR *message.InfraIR
+}
+
+type Runner struct {
+	Config
+}
+
+// New creates a new global rate limit runner.
+func New(cfg *Config) *Runner {
+	return &Runner{Config: *cfg}
+}
+
+// Start starts the global rate limit runner. It subscribes to the XdsIR
+// message and, whenever a gateway has rate limit descriptors configured,
+// publishes the infra resources required to run the ratelimit service to
+// InfraIR so the infrastructure runner can reconcile them.
+func (r *Runner) Start(ctx context.Context) error {
+	r.Logger = r.Logger.WithValues("runner", "global-ratelimit")
+	go r.subscribeAndTranslate(ctx)
+	r.Logger.Info("started")
+	return nil
+}
+
+func (r *Runner) subscribeAndTranslate(ctx context.Context) {
+	message.HandleSubscription(r.XdsIR.Subscribe(ctx), func(update message.Update[string, *ir.Xds]) {
+		r.Logger.Info("received an update", "key", update.Key)
+
+		if update.Delete || !needsRateLimitService(update.Value) {
+			// Either the Gateway was removed, or it no longer has any rate
+			// limited routes; either way the ratelimit service Infra we may
+			// have published for it earlier is now stale and must be
+			// removed, or it lingers running forever.
+			r.InfraIR.Delete(update.Key)
+			return
+		}
+
+		r.InfraIR.Store(update.Key, r.buildInfraIR(update.Value))
+	})
+	r.Logger.Info("shutting down")
+}
+
+// needsRateLimitService returns true if any of the HTTP routes in xdsIR
+// reference a rate limit descriptor, meaning the ratelimit service
+// deployment is required.
+func needsRateLimitService(xdsIR *ir.Xds) bool {
+	if xdsIR == nil {
+		return false
+	}
+	for _, listener := range xdsIR.HTTP {
+		for _, route := range listener.Routes {
+			if route.RateLimit != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildInfraIR builds the infra IR resources (Redis-backed deployment,
+// service and ConfigMap) required to run the ratelimit service for the
+// given xDS IR. The infra runner turns this into the actual Deployment,
+// Service and ConfigMap.
+func (r *Runner) buildInfraIR(xdsIR *ir.Xds) *ir.Infra {
+	infraIR := ir.NewInfra()
+	infraIR.Proxy.Name = infraResourceName
+	infraIR.Proxy.Image = rateLimitServiceImage
+	infraIR.Proxy.Replicas = 1
+	infraIR.Proxy.Ports = []int32{rateLimitGRPCPort}
+	infraIR.Proxy.Config = map[string]string{
+		rateLimitConfigFile: buildRateLimitServiceConfig(xdsIR),
+	}
+	return infraIR
+}
+
+// rateLimitServiceConfig is the descriptor config file format the upstream
+// ratelimit service reads, one domain per HTTP listener.
+type rateLimitServiceConfig struct {
+	Domain      string                 `json:"domain"`
+	Descriptors []rateLimitConfigEntry `json:"descriptors"`
+}
+
+type rateLimitConfigEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// buildRateLimitServiceConfig renders the ratelimit service's descriptor
+// config file for every rate-limited listener in xdsIR, one domain per
+// listener. The domain must match translator.PatchRateLimit's listenerName,
+// since that's the domain the envoy.filters.http.ratelimit filter shared by
+// every route on the listener actually sends in its requests to this
+// service; keying by route instead would leave every route but the first to
+// install the filter querying a domain this config never renders, so the
+// service would deny them outright under FailureModeDeny.
+//
+// The Redis connection itself is supplied to the ratelimit service
+// container via the standard REDIS_SOCKET_TYPE/REDIS_URL environment
+// variables, populated from EnvoyGateway.RateLimit.Backend.Redis by the
+// infra runner.
+func buildRateLimitServiceConfig(xdsIR *ir.Xds) string {
+	var configs []rateLimitServiceConfig
+	for _, listener := range xdsIR.HTTP {
+		var entries []rateLimitConfigEntry
+		for _, route := range listener.Routes {
+			if route.RateLimit == nil {
+				continue
+			}
+			for _, rule := range route.RateLimit.Rules {
+				entries = append(entries, rateLimitConfigEntry{Key: rule.Key, Value: rule.Value})
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		configs = append(configs, rateLimitServiceConfig{
+			Domain:      listener.Name,
+			Descriptors: entries,
+		})
+	}
+
+	out, err := yaml.Marshal(configs)
+	if err != nil {
+		// yaml.Marshal only fails on unsupported types; rateLimitServiceConfig
+		// contains none, so this is unreachable in practice.
+		return fmt.Sprintf("# failed to render ratelimit config: %v\n", err)
+	}
+	return string(out)
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/envoyproxy/gateway/internal/ir"
+)
+
+func TestNeedsRateLimitService(t *testing.T) {
+	cases := []struct {
+		name string
+		xds  *ir.Xds
+		want bool
+	}{
+		{name: "nil", xds: nil, want: false},
+		{name: "no routes", xds: &ir.Xds{}, want: false},
+		{
+			name: "no rate limited routes",
+			xds: &ir.Xds{HTTP: []*ir.HTTPListener{{
+				Routes: []*ir.HTTPRoute{{Name: "a"}},
+			}}},
+			want: false,
+		},
+		{
+			name: "one rate limited route",
+			xds: &ir.Xds{HTTP: []*ir.HTTPListener{{
+				Routes: []*ir.HTTPRoute{
+					{Name: "a"},
+					{Name: "b", RateLimit: &ir.RateLimit{Rules: []*ir.RateLimitRule{{Key: "remote_address"}}}},
+				},
+			}}},
+			want: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := needsRateLimitService(c.xds); got != c.want {
+				t.Errorf("needsRateLimitService() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestBuildRateLimitServiceConfig(t *testing.T) {
+	xdsIR := &ir.Xds{HTTP: []*ir.HTTPListener{{
+		Name: "gateway-1-listener",
+		Routes: []*ir.HTTPRoute{
+			{Name: "unlimited"},
+			{
+				Name: "httpbin",
+				RateLimit: &ir.RateLimit{
+					Rules: []*ir.RateLimitRule{
+						{Key: "remote_address"},
+						{Key: "header_match", Value: "premium-user"},
+					},
+				},
+			},
+		},
+	}}}
+
+	out := buildRateLimitServiceConfig(xdsIR)
+
+	// The domain must be the listener's name, not any one route's: every
+	// route on the listener shares a single envoy.filters.http.ratelimit
+	// filter keyed by the listener's domain (translator.PatchRateLimit only
+	// installs it once per listener), so a per-route domain here would
+	// never match what most of those routes actually send.
+	if !strings.Contains(out, "domain: gateway-1-listener") {
+		t.Errorf("expected rendered config to contain the listener's domain, got:\n%s", out)
+	}
+	if strings.Contains(out, "unlimited") {
+		t.Errorf("expected rendered config to omit the non-rate-limited route, got:\n%s", out)
+	}
+	if !strings.Contains(out, "premium-user") {
+		t.Errorf("expected rendered config to contain the descriptor value, got:\n%s", out)
+	}
+}
@@ -0,0 +1,30 @@
+package ir
+
+// Infra is the infra IR published for a single Gateway; it describes the
+// Kubernetes (or other platform) resources the infra runner must reconcile.
+type Infra struct {
+	// Proxy describes the Envoy Proxy (or, for the global ratelimit runner,
+	// the ratelimit service) deployment to reconcile.
+	Proxy *ProxyInfra
+}
+
+// ProxyInfra is the infra IR for a single managed deployment.
+type ProxyInfra struct {
+	Name string
+
+	// Image is the container image to run, e.g.
+	// "envoyproxy/ratelimit:master".
+	Image string
+	// Replicas is the desired replica count of the Deployment.
+	Replicas int32
+	// Config holds the data to publish in the deployment's ConfigMap,
+	// keyed by file name.
+	Config map[string]string
+	// Ports are the container ports the Service should expose.
+	Ports []int32
+}
+
+// NewInfra returns an empty Infra ready to be populated.
+func NewInfra() *Infra {
+	return &Infra{Proxy: new(ProxyInfra)}
+}
@@ -0,0 +1,45 @@
+// Package ir defines the intermediate representation the GatewayAPI
+// translator produces and the xDS translator and infra runner consume.
+//
+// This file only carries the subset of the IR referenced by the packages
+// added alongside it (notably the global rate-limit runner and its xDS
+// translation); the rest of the IR is assumed to already exist upstream.
+package ir
+
+// Xds is the xDS IR published for a single Gateway.
+type Xds struct {
+	// HTTP holds one entry per HTTP/HTTPS listener on the Gateway.
+	HTTP []*HTTPListener
+}
+
+// HTTPListener is the xDS IR for a single HTTP/HTTPS listener.
+type HTTPListener struct {
+	Name   string
+	Routes []*HTTPRoute
+}
+
+// HTTPRoute is the xDS IR for a single HTTPRoute rule.
+type HTTPRoute struct {
+	Name string
+
+	// RateLimit holds the rate limit descriptors that apply to this route,
+	// derived from the HTTPRoute's BackendRefs/ExtensionRefs/filters. A nil
+	// RateLimit means the route isn't rate limited.
+	RateLimit *RateLimit
+}
+
+// RateLimit is the IR for a route's rate limit configuration, translated
+// into the envoy.filters.http.ratelimit filter and the descriptor
+// hierarchy served by the ratelimit service.
+type RateLimit struct {
+	// Rules each contribute one entry, in order, to the single descriptor
+	// sent to the ratelimit service; every rule applies on every request.
+	Rules []*RateLimitRule
+}
+
+// RateLimitRule is a single descriptor entry, e.g. {Key: "remote_address"}
+// or {Key: "header_match", Value: "premium-user"}.
+type RateLimitRule struct {
+	Key   string
+	Value string
+}
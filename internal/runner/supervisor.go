@@ -0,0 +1,276 @@
+// Package runner provides a small supervisor, modeled on the Arvados boot
+// supervisor, for starting, health-checking and shutting down the set of
+// runners that make up the Envoy Gateway control plane.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Runner is anything the Supervisor can manage. Name and DependsOn are used
+// to order startup/shutdown; Start should block until ctx is done (or the
+// runner fails) and Healthy reports the runner's current status.
+type Runner interface {
+	// Name uniquely identifies the runner, e.g. "provider", "xds-translator".
+	Name() string
+	// DependsOn lists the names of runners that must be started, and whose
+	// message queues must remain open, before this runner starts.
+	DependsOn() []string
+	// Start starts the runner and blocks until ctx is canceled or the
+	// runner encounters a non-recoverable error.
+	Start(ctx context.Context) error
+	// Healthy reports a non-nil error when the runner is degraded.
+	Healthy() error
+}
+
+// minBackoff and maxBackoff bound the delay between restart attempts for a
+// runner whose Start returns before ctx is done.
+const (
+	minBackoff = 1 * time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// startupHealthPollInterval is how often Start polls a just-launched
+// runner's Healthy method while waiting for it to come up before launching
+// the next runner in dependency order.
+const startupHealthPollInterval = 10 * time.Millisecond
+
+// Supervisor starts a set of Runners in dependency order, restarts any that
+// exit early, aggregates their health into /healthz and /readyz, and shuts
+// them down in reverse dependency order on context cancellation.
+type Supervisor struct {
+	logger     logr.Logger
+	healthAddr string
+
+	mu      sync.Mutex
+	runners []Runner
+	status  map[string]error
+}
+
+// NewSupervisor creates a Supervisor that serves aggregate health on
+// healthAddr (e.g. ":8081"). An empty healthAddr disables the health server.
+func NewSupervisor(logger logr.Logger, healthAddr string) *Supervisor {
+	return &Supervisor{
+		logger:     logger.WithValues("component", "supervisor"),
+		healthAddr: healthAddr,
+		status:     make(map[string]error),
+	}
+}
+
+// Register adds a runner to the supervisor. Runners must be registered
+// before Start is called, and in an order consistent with their
+// dependencies (a runner's dependencies must already be registered).
+func (s *Supervisor) Register(r Runner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runners = append(s.runners, r)
+	s.status[r.Name()] = fmt.Errorf("not yet started")
+}
+
+// Start orders the registered runners by their dependencies, starts each one
+// (restarting it with backoff if it exits before ctx is done), serves the
+// health endpoints, and blocks until ctx is canceled, at which point it
+// shuts every runner down in reverse dependency order.
+func (s *Supervisor) Start(ctx context.Context) error {
+	ordered, err := s.orderByDependency()
+	if err != nil {
+		return err
+	}
+
+	if s.healthAddr != "" {
+		s.startHealthServer(ctx)
+	}
+
+	// Each runner gets its own context derived from context.Background(),
+	// not ctx: deriving every one of them from the already-canceling
+	// parent ctx would have context.WithCancel propagate ctx's
+	// cancellation to all of them simultaneously, making the reverse-order
+	// cancel loop below a no-op. Cancellation instead only ever flows
+	// through the per-runner cancel funcs, which the loop fires one at a
+	// time.
+	cancels := make([]context.CancelFunc, len(ordered))
+	done := make([]chan struct{}, len(ordered))
+
+	// Launch runners one at a time in dependency order, waiting for each to
+	// report healthy before launching the next, so a runner's dependencies
+	// are actually up (not just enqueued to start) by the time it starts.
+	started := 0
+launch:
+	for i, r := range ordered {
+		select {
+		case <-ctx.Done():
+			break launch
+		default:
+		}
+
+		rCtx, cancel := context.WithCancel(context.Background())
+		cancels[i] = cancel
+		done[i] = make(chan struct{})
+
+		go func(i int, r Runner, rCtx context.Context) {
+			defer close(done[i])
+			s.runWithRestart(rCtx, r)
+		}(i, r, rCtx)
+		started = i + 1
+
+		if !s.waitHealthy(ctx, r) {
+			break launch
+		}
+	}
+
+	<-ctx.Done()
+	s.logger.Info("shutting down runners")
+
+	// Shut down one runner at a time, in reverse dependency order, waiting
+	// for each to actually exit before canceling the next, so a runner
+	// never outlives the message queues it depends on. Only the runners
+	// actually launched above need shutting down: ctx may have been
+	// canceled before every runner got a chance to start.
+	for i := started - 1; i >= 0; i-- {
+		cancels[i]()
+		<-done[i]
+	}
+
+	return nil
+}
+
+// waitHealthy polls r.Healthy until it reports nil or ctx is done, whichever
+// comes first. It returns false when ctx wins the race, signaling Start's
+// launch loop to stop launching further runners.
+func (s *Supervisor) waitHealthy(ctx context.Context, r Runner) bool {
+	ticker := time.NewTicker(startupHealthPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if r.Healthy() == nil {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// runWithRestart runs r.Start in a loop, applying exponential backoff
+// between attempts, until ctx is done.
+func (s *Supervisor) runWithRestart(ctx context.Context, r Runner) {
+	backoff := minBackoff
+	for {
+		err := r.Start(ctx)
+		s.setStatus(r.Name(), err)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			s.logger.Error(err, "runner exited, restarting", "runner", r.Name(), "backoff", backoff)
+		} else {
+			s.logger.Info("runner exited before shutdown, restarting", "runner", r.Name(), "backoff", backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (s *Supervisor) setStatus(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status[name] = err
+}
+
+// orderByDependency performs a stable topological sort of the registered
+// runners based on DependsOn.
+func (s *Supervisor) orderByDependency() ([]Runner, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byName := make(map[string]Runner, len(s.runners))
+	for _, r := range s.runners {
+		byName[r.Name()] = r
+	}
+
+	var ordered []Runner
+	visited := make(map[string]int) // 0=unvisited, 1=visiting, 2=done
+	var visit func(r Runner) error
+	visit = func(r Runner) error {
+		switch visited[r.Name()] {
+		case 2:
+			return nil
+		case 1:
+			return fmt.Errorf("dependency cycle detected at runner %q", r.Name())
+		}
+		visited[r.Name()] = 1
+		for _, dep := range r.DependsOn() {
+			depRunner, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("runner %q depends on unregistered runner %q", r.Name(), dep)
+			}
+			if err := visit(depRunner); err != nil {
+				return err
+			}
+		}
+		visited[r.Name()] = 2
+		ordered = append(ordered, r)
+		return nil
+	}
+
+	for _, r := range s.runners {
+		if err := visit(r); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// startHealthServer serves /healthz and /readyz (both: any runner unhealthy
+// -> 503, otherwise 200) until ctx is done.
+func (s *Supervisor) startHealthServer(ctx context.Context) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleHealth)
+
+	srv := &http.Server{Addr: s.healthAddr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Error(err, "health server exited")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+}
+
+func (s *Supervisor) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	runners := append([]Runner(nil), s.runners...)
+	s.mu.Unlock()
+
+	for _, r := range runners {
+		if err := r.Healthy(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "%s: %v\n", r.Name(), err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,188 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// fakeRunner is a minimal Runner used only to exercise orderByDependency.
+type fakeRunner struct {
+	name string
+	deps []string
+}
+
+func (f *fakeRunner) Name() string                   { return f.name }
+func (f *fakeRunner) DependsOn() []string             { return f.deps }
+func (f *fakeRunner) Start(ctx context.Context) error { <-ctx.Done(); return nil }
+func (f *fakeRunner) Healthy() error                  { return nil }
+
+func namesOf(runners []Runner) []string {
+	names := make([]string, len(runners))
+	for i, r := range runners {
+		names[i] = r.Name()
+	}
+	return names
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestOrderByDependency(t *testing.T) {
+	s := NewSupervisor(logr.Discard(), "")
+	s.Register(&fakeRunner{name: "provider"})
+	s.Register(&fakeRunner{name: "gatewayapi", deps: []string{"provider"}})
+	s.Register(&fakeRunner{name: "xds-translator", deps: []string{"gatewayapi"}})
+	s.Register(&fakeRunner{name: "infra", deps: []string{"gatewayapi"}})
+
+	ordered, err := s.orderByDependency()
+	if err != nil {
+		t.Fatalf("orderByDependency returned an error: %v", err)
+	}
+	names := namesOf(ordered)
+
+	if indexOf(names, "provider") > indexOf(names, "gatewayapi") {
+		t.Errorf("expected provider before gatewayapi, got order %v", names)
+	}
+	if indexOf(names, "gatewayapi") > indexOf(names, "xds-translator") {
+		t.Errorf("expected gatewayapi before xds-translator, got order %v", names)
+	}
+	if indexOf(names, "gatewayapi") > indexOf(names, "infra") {
+		t.Errorf("expected gatewayapi before infra, got order %v", names)
+	}
+}
+
+func TestOrderByDependencyDetectsCycle(t *testing.T) {
+	s := NewSupervisor(logr.Discard(), "")
+	s.Register(&fakeRunner{name: "a", deps: []string{"b"}})
+	s.Register(&fakeRunner{name: "b", deps: []string{"a"}})
+
+	if _, err := s.orderByDependency(); err == nil {
+		t.Fatal("expected a dependency cycle error, got nil")
+	}
+}
+
+func TestOrderByDependencyUnregisteredDependency(t *testing.T) {
+	s := NewSupervisor(logr.Discard(), "")
+	s.Register(&fakeRunner{name: "a", deps: []string{"missing"}})
+
+	if _, err := s.orderByDependency(); err == nil {
+		t.Fatal("expected an unregistered-dependency error, got nil")
+	}
+}
+
+// orderedRunner records its name on exited once ctx is done, after sleeping
+// for delay, so tests can observe the actual order runners stop in.
+type orderedRunner struct {
+	name   string
+	deps   []string
+	delay  time.Duration
+	exited chan string
+}
+
+func (r *orderedRunner) Name() string       { return r.name }
+func (r *orderedRunner) DependsOn() []string { return r.deps }
+func (r *orderedRunner) Healthy() error      { return nil }
+func (r *orderedRunner) Start(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(r.delay)
+	r.exited <- r.name
+	return nil
+}
+
+func TestStartShutsDownOneRunnerAtATimeInReverseDependencyOrder(t *testing.T) {
+	exited := make(chan string, 2)
+	s := NewSupervisor(logr.Discard(), "")
+	// b depends on a, so a must still be up when b is told to stop, and
+	// must not be canceled until b has actually exited.
+	s.Register(&orderedRunner{name: "a", exited: exited})
+	s.Register(&orderedRunner{name: "b", deps: []string{"a"}, delay: 50 * time.Millisecond, exited: exited})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = s.Start(ctx)
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond) // let both runners start
+	cancel()
+
+	first := <-exited
+	second := <-exited
+	if first != "b" || second != "a" {
+		t.Errorf("expected shutdown order [b, a], got [%s, %s]", first, second)
+	}
+	<-done
+}
+
+// slowHealthRunner stays unhealthy until its ready channel is closed, so
+// tests can observe whether a dependent runner was launched before its
+// dependency actually became healthy.
+type slowHealthRunner struct {
+	name    string
+	deps    []string
+	ready   chan struct{}
+	started atomic.Bool
+}
+
+func (r *slowHealthRunner) Name() string       { return r.name }
+func (r *slowHealthRunner) DependsOn() []string { return r.deps }
+func (r *slowHealthRunner) Start(ctx context.Context) error {
+	r.started.Store(true)
+	<-ctx.Done()
+	return nil
+}
+func (r *slowHealthRunner) Healthy() error {
+	select {
+	case <-r.ready:
+		return nil
+	default:
+		return errors.New("not ready yet")
+	}
+}
+
+func TestStartWaitsForDependencyToBeHealthyBeforeStartingDependent(t *testing.T) {
+	a := &slowHealthRunner{name: "a", ready: make(chan struct{})}
+	b := &slowHealthRunner{name: "b", deps: []string{"a"}, ready: make(chan struct{})}
+	close(b.ready)
+
+	s := NewSupervisor(logr.Discard(), "")
+	s.Register(a)
+	s.Register(b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = s.Start(ctx)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if !a.started.Load() {
+		t.Fatal("expected a to have been launched")
+	}
+	if b.started.Load() {
+		t.Error("expected b to still be waiting on a's Healthy, but b was launched")
+	}
+
+	close(a.ready)
+	time.Sleep(50 * time.Millisecond)
+	if !b.started.Load() {
+		t.Error("expected b to start once a became healthy")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,63 @@
+package troubleshoot
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DiscrepancyKind classifies a mismatch between the translated and live
+// configuration.
+type DiscrepancyKind string
+
+const (
+	// KindMissing means a resource the translator published was never seen
+	// in the Envoy's live config_dump.
+	KindMissing DiscrepancyKind = "Missing"
+	// KindStale means a resource exists on both sides but its contents
+	// differ, e.g. a stale route hash or an endpoint count mismatch.
+	KindStale DiscrepancyKind = "Stale"
+)
+
+// Discrepancy describes a single resource that didn't reconcile cleanly
+// between the translated xDS resources and the live Envoy config_dump.
+type Discrepancy struct {
+	Kind    DiscrepancyKind
+	TypeURL string
+	Name    string
+	NodeID  string
+	Detail  string
+}
+
+// Diff compares the resources the xDS translator most recently published
+// (want) against the live, indexed config_dump (got), returning the set of
+// discrepancies found.
+func Diff(nodeID string, want map[string]proto.Message, got ResourceIndex, typeURL string) []Discrepancy {
+	var discrepancies []Discrepancy
+
+	for name, wantMsg := range want {
+		gotMsg, ok := got.Get(typeURL, name)
+		if !ok {
+			discrepancies = append(discrepancies, Discrepancy{
+				Kind:    KindMissing,
+				TypeURL: typeURL,
+				Name:    name,
+				NodeID:  nodeID,
+				Detail:  fmt.Sprintf("resource %q was translated but not found in the live config_dump", name),
+			})
+			continue
+		}
+
+		if !proto.Equal(wantMsg, gotMsg) {
+			discrepancies = append(discrepancies, Discrepancy{
+				Kind:    KindStale,
+				TypeURL: typeURL,
+				Name:    name,
+				NodeID:  nodeID,
+				Detail:  fmt.Sprintf("resource %q differs between the translated config and the live config_dump", name),
+			})
+		}
+	}
+
+	return discrepancies
+}
@@ -0,0 +1,56 @@
+package troubleshoot
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDiffMissing(t *testing.T) {
+	want := map[string]proto.Message{
+		"my-cluster": &clusterv3.Cluster{Name: "my-cluster"},
+	}
+	got := make(ResourceIndex)
+
+	discrepancies := Diff("node-1", want, got, resourceTypeURL)
+	if len(discrepancies) != 1 {
+		t.Fatalf("expected 1 discrepancy, got %d", len(discrepancies))
+	}
+	if discrepancies[0].Kind != KindMissing {
+		t.Errorf("Kind = %v, want %v", discrepancies[0].Kind, KindMissing)
+	}
+	if discrepancies[0].Name != "my-cluster" {
+		t.Errorf("Name = %q, want %q", discrepancies[0].Name, "my-cluster")
+	}
+}
+
+func TestDiffStale(t *testing.T) {
+	want := map[string]proto.Message{
+		"my-cluster": &clusterv3.Cluster{Name: "my-cluster", ConnectTimeout: nil},
+	}
+	got := make(ResourceIndex)
+	got.store(resourceTypeURL, "my-cluster", &clusterv3.Cluster{Name: "my-cluster", AltStatName: "different"})
+
+	discrepancies := Diff("node-1", want, got, resourceTypeURL)
+	if len(discrepancies) != 1 {
+		t.Fatalf("expected 1 discrepancy, got %d", len(discrepancies))
+	}
+	if discrepancies[0].Kind != KindStale {
+		t.Errorf("Kind = %v, want %v", discrepancies[0].Kind, KindStale)
+	}
+}
+
+func TestDiffNoDiscrepancy(t *testing.T) {
+	want := map[string]proto.Message{
+		"my-cluster": &clusterv3.Cluster{Name: "my-cluster"},
+	}
+	got := make(ResourceIndex)
+	got.store(resourceTypeURL, "my-cluster", &clusterv3.Cluster{Name: "my-cluster"})
+
+	if discrepancies := Diff("node-1", want, got, resourceTypeURL); len(discrepancies) != 0 {
+		t.Errorf("expected no discrepancies, got %d", len(discrepancies))
+	}
+}
+
+const resourceTypeURL = "type.googleapis.com/envoy.config.cluster.v3.Cluster"
@@ -0,0 +1,106 @@
+package troubleshoot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	adminv3 "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// adminPort is the port Envoy's admin interface listens on in the managed
+// proxy Deployments.
+const adminPort = 19000
+
+// owningGatewayNamespaceLabel and owningGatewayNameLabel are set by the
+// infra runner on every managed proxy pod, identifying the Gateway the pod
+// serves traffic for.
+const (
+	owningGatewayNamespaceLabel = "gateway.envoyproxy.io/owning-gateway-namespace"
+	owningGatewayNameLabel      = "gateway.envoyproxy.io/owning-gateway-name"
+)
+
+// ConfigDumpFetcher returns the parsed /config_dump for each Envoy proxy pod
+// managed by the infra runner, keyed by node ID. A non-nil error means at
+// least one pod's /config_dump couldn't be fetched; the returned map still
+// holds every pod that succeeded, so callers should keep processing it
+// rather than discard the batch.
+type ConfigDumpFetcher interface {
+	FetchConfigDumps(ctx context.Context) (map[string]*adminv3.ConfigDump, error)
+}
+
+// PodAdminFetcher fetches /config_dump directly from each managed Envoy
+// proxy pod's admin address, reachable from within the cluster.
+type PodAdminFetcher struct {
+	Client    client.Client
+	Namespace string
+	// Selector selects the managed Envoy proxy pods, e.g. the labels set by
+	// the infra runner on the proxy Deployment's pod template.
+	Selector client.MatchingLabels
+}
+
+// FetchConfigDumps lists the managed proxy pods and issues a /config_dump
+// request against each one's admin address, keyed by the node ID
+// "<namespace>/<name>" of the Gateway the pod serves, recovered from its
+// owning-gateway labels. Pods missing those labels are skipped, since there
+// is no Gateway to report a discrepancy against.
+//
+// One pod's /config_dump request failing (e.g. a pod mid-restart) doesn't
+// abort the rest of the batch: its error is collected and joined into the
+// returned error, but every other pod's dump still comes back in the map.
+func (f *PodAdminFetcher) FetchConfigDumps(ctx context.Context) (map[string]*adminv3.ConfigDump, error) {
+	var pods corev1.PodList
+	if err := f.Client.List(ctx, &pods, client.InNamespace(f.Namespace), f.Selector); err != nil {
+		return nil, fmt.Errorf("failed to list managed proxy pods: %w", err)
+	}
+
+	dumps := make(map[string]*adminv3.ConfigDump, len(pods.Items))
+	var errs []error
+	for _, pod := range pods.Items {
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		gwNamespace, gwName := pod.Labels[owningGatewayNamespaceLabel], pod.Labels[owningGatewayNameLabel]
+		if gwNamespace == "" || gwName == "" {
+			continue
+		}
+
+		dump, err := fetchConfigDump(ctx, pod.Status.PodIP)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch config_dump from pod %s: %w", pod.Name, err))
+			continue
+		}
+		dumps[gwNamespace+"/"+gwName] = dump
+	}
+	return dumps, errors.Join(errs...)
+}
+
+func fetchConfigDump(ctx context.Context, podIP string) (*adminv3.ConfigDump, error) {
+	url := fmt.Sprintf("http://%s:%d/config_dump", podIP, adminPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	dump := new(adminv3.ConfigDump)
+	if err := protojson.Unmarshal(body, dump); err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
@@ -0,0 +1,93 @@
+// Package troubleshoot periodically cross-checks the xDS configuration
+// translated by Envoy Gateway against the /config_dump actually served by
+// the managed Envoy proxies, surfacing discrepancies as Gateway status
+// conditions and structured log events.
+package troubleshoot
+
+import (
+	adminv3 "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// ResourceIndex is an indexed view of an envoy.admin.v3.ConfigDump, keyed by
+// type URL and then by resource name, mirroring the approach used by the
+// Consul troubleshoot package so lookups during diffing are O(1).
+type ResourceIndex map[string]map[string]proto.Message
+
+// IndexConfigDump unmarshals a ConfigDump's typed sub-dumps
+// (ListenersConfigDump, ClustersConfigDump, RoutesConfigDump,
+// EndpointsConfigDump) into a ResourceIndex.
+func IndexConfigDump(dump *adminv3.ConfigDump) (ResourceIndex, error) {
+	idx := make(ResourceIndex)
+
+	for _, a := range dump.GetConfigs() {
+		msg, err := a.UnmarshalNew()
+		if err != nil {
+			return nil, err
+		}
+
+		switch d := msg.(type) {
+		case *adminv3.ListenersConfigDump:
+			for _, l := range d.GetDynamicListeners() {
+				active := l.GetActiveState()
+				if active == nil {
+					continue
+				}
+				listener := new(listenerv3.Listener)
+				if err := active.GetListener().UnmarshalTo(listener); err != nil {
+					return nil, err
+				}
+				idx.store(active.GetListener().GetTypeUrl(), l.GetName(), listener)
+			}
+		case *adminv3.ClustersConfigDump:
+			for _, c := range d.GetDynamicActiveClusters() {
+				cluster := new(clusterv3.Cluster)
+				if err := c.GetCluster().UnmarshalTo(cluster); err != nil {
+					return nil, err
+				}
+				idx.store(c.GetCluster().GetTypeUrl(), cluster.GetName(), cluster)
+			}
+		case *adminv3.RoutesConfigDump:
+			for _, r := range d.GetDynamicRouteConfigs() {
+				routeCfg := new(routev3.RouteConfiguration)
+				if err := r.GetRouteConfig().UnmarshalTo(routeCfg); err != nil {
+					return nil, err
+				}
+				idx.store(r.GetRouteConfig().GetTypeUrl(), routeCfg.GetName(), routeCfg)
+			}
+		case *adminv3.EndpointsConfigDump:
+			for _, e := range d.GetDynamicEndpointConfigs() {
+				cla := new(endpointv3.ClusterLoadAssignment)
+				if err := e.GetEndpointConfig().UnmarshalTo(cla); err != nil {
+					return nil, err
+				}
+				idx.store(e.GetEndpointConfig().GetTypeUrl(), cla.GetClusterName(), cla)
+			}
+		}
+	}
+
+	return idx, nil
+}
+
+func (idx ResourceIndex) store(typeURL, name string, msg proto.Message) {
+	byName, ok := idx[typeURL]
+	if !ok {
+		byName = make(map[string]proto.Message)
+		idx[typeURL] = byName
+	}
+	byName[name] = msg
+}
+
+// Get looks up a resource by type URL and name in O(1).
+func (idx ResourceIndex) Get(typeURL, name string) (proto.Message, bool) {
+	byName, ok := idx[typeURL]
+	if !ok {
+		return nil, false
+	}
+	msg, ok := byName[name]
+	return msg, ok
+}
@@ -0,0 +1,28 @@
+package troubleshoot
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+)
+
+func TestResourceIndexStoreAndGet(t *testing.T) {
+	idx := make(ResourceIndex)
+	cluster := &clusterv3.Cluster{Name: "my-cluster"}
+	idx.store(resourceTypeURL, "my-cluster", cluster)
+
+	got, ok := idx.Get(resourceTypeURL, "my-cluster")
+	if !ok {
+		t.Fatal("expected to find the stored resource")
+	}
+	if got != cluster {
+		t.Errorf("Get returned a different message than was stored")
+	}
+
+	if _, ok := idx.Get(resourceTypeURL, "missing"); ok {
+		t.Error("expected no resource for an unknown name")
+	}
+	if _, ok := idx.Get("unknown-type", "my-cluster"); ok {
+		t.Error("expected no resource for an unknown type URL")
+	}
+}
@@ -0,0 +1,222 @@
+// Package runner periodically validates that the xDS configuration the
+// translator published was actually accepted and applied by the managed
+// Envoy proxies.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+	"github.com/envoyproxy/gateway/internal/message"
+	"github.com/envoyproxy/gateway/internal/troubleshoot"
+)
+
+// conditionTypeConfigReconciled is the Gateway status condition type set by
+// the troubleshoot runner, reporting whether the translated xDS
+// configuration matches what the managed Envoy proxies actually loaded.
+const conditionTypeConfigReconciled = "ConfigurationReconciled"
+
+// watchedTypeURLs are the resource types the differ cross-checks; these are
+// the same four sub-dumps IndexConfigDump understands.
+var watchedTypeURLs = []string{
+	resourcev3.ListenerType,
+	resourcev3.ClusterType,
+	resourcev3.RouteType,
+	resourcev3.EndpointType,
+}
+
+// defaultInterval is how often managed Envoy proxies are polled for their
+// live config_dump.
+const defaultInterval = 30 * time.Second
+
+type Config struct {
+	config.Server
+	XdsResources *message.XdsResources
+	Fetcher      troubleshoot.ConfigDumpFetcher
+	// Client is used to surface discrepancies as status conditions on the
+	// Gateway each node ID belongs to.
+	Client client.Client
+}
+
+type Runner struct {
+	Config
+
+	// lastReconcileErr is the error (if any) from the most recently completed
+	// reconcile, so Healthy can report actual fetch failures instead of
+	// always claiming success. nil until the first tick completes.
+	lastReconcileErrMu sync.RWMutex
+	lastReconcileErr   error
+	reconciledOnce     bool
+}
+
+// New creates a new troubleshoot runner.
+func New(cfg *Config) *Runner {
+	return &Runner{Config: *cfg}
+}
+
+// Name implements runner.Runner.
+func (r *Runner) Name() string { return "troubleshoot" }
+
+// DependsOn implements runner.Runner: discrepancies are only meaningful
+// once the translator and infra runners have had a chance to publish and
+// deploy something.
+func (r *Runner) DependsOn() []string {
+	return []string{"xds-translator", "infra"}
+}
+
+// Healthy implements runner.Runner: healthy once the first reconcile has
+// completed without a fetch error. A pod going temporarily unreachable
+// surfaces here instead of being silently swallowed.
+func (r *Runner) Healthy() error {
+	r.lastReconcileErrMu.RLock()
+	defer r.lastReconcileErrMu.RUnlock()
+	if !r.reconciledOnce {
+		return errors.New("no config_dump reconcile has completed yet")
+	}
+	return r.lastReconcileErr
+}
+
+// Start polls the managed Envoy proxies' config_dump every defaultInterval,
+// diffing it against the most recently published XdsResources, until ctx is
+// done.
+func (r *Runner) Start(ctx context.Context) error {
+	r.Logger = r.Logger.WithValues("runner", "troubleshoot")
+	r.Logger.Info("started")
+
+	ticker := time.NewTicker(defaultInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.Logger.Info("shutting down")
+			return nil
+		case <-ticker.C:
+			r.reconcile(ctx)
+		}
+	}
+}
+
+func (r *Runner) reconcile(ctx context.Context) {
+	// A non-nil err here means one or more pods' /config_dump couldn't be
+	// fetched; dumps still holds every pod that succeeded, so reconciling
+	// continues for those rather than skipping the whole cycle over one
+	// flaky pod.
+	dumps, err := r.Fetcher.FetchConfigDumps(ctx)
+	if err != nil {
+		r.Logger.Error(err, "failed to fetch config_dump from one or more managed Envoy proxies, reconciling the rest")
+	}
+
+	r.lastReconcileErrMu.Lock()
+	r.lastReconcileErr = err
+	r.reconciledOnce = true
+	r.lastReconcileErrMu.Unlock()
+
+	for nodeID, dump := range dumps {
+		idx, err := troubleshoot.IndexConfigDump(dump)
+		if err != nil {
+			r.Logger.Error(err, "failed to index config_dump", "nodeID", nodeID)
+			continue
+		}
+
+		snapshot, ok := r.loadSnapshot(nodeID)
+		if !ok {
+			r.Logger.Info("no published xDS snapshot for node, skipping reconcile", "nodeID", nodeID)
+			continue
+		}
+
+		var discrepancies []troubleshoot.Discrepancy
+		for _, typeURL := range watchedTypeURLs {
+			want := toProtoMessages(snapshot.GetResources(typeURL))
+			for _, d := range troubleshoot.Diff(nodeID, want, idx, typeURL) {
+				r.Logger.Info("discrepancy between translated and live xDS config",
+					"kind", d.Kind, "nodeID", d.NodeID, "typeURL", d.TypeURL, "name", d.Name, "detail", d.Detail)
+				discrepancies = append(discrepancies, d)
+			}
+		}
+
+		r.updateGatewayStatus(ctx, nodeID, discrepancies)
+	}
+}
+
+// loadSnapshot returns the most recently published xDS snapshot for nodeID,
+// if the translator has published one yet. Diffing against every other
+// node's snapshot would misreport every resource the current node doesn't
+// happen to share as "Missing".
+func (r *Runner) loadSnapshot(nodeID string) (*cachev3.Snapshot, bool) {
+	for _, kv := range r.XdsResources.LoadAll() {
+		if kv.Key == nodeID {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
+
+// updateGatewayStatus surfaces discrepancies found for nodeID as a
+// ConfigurationReconciled status condition on the Gateway it identifies.
+func (r *Runner) updateGatewayStatus(ctx context.Context, nodeID string, discrepancies []troubleshoot.Discrepancy) {
+	namespace, name, ok := splitNodeID(nodeID)
+	if !ok {
+		r.Logger.Info("node ID is not a Gateway reference, skipping status update", "nodeID", nodeID)
+		return
+	}
+
+	var gw gwapiv1.Gateway
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &gw); err != nil {
+		r.Logger.Error(err, "failed to get Gateway for status update", "namespace", namespace, "name", name)
+		return
+	}
+
+	cond := metav1.Condition{
+		Type:               conditionTypeConfigReconciled,
+		ObservedGeneration: gw.Generation,
+	}
+	if len(discrepancies) == 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "ConfigReconciled"
+		cond.Message = "translated xDS configuration matches the live Envoy config_dump"
+	} else {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "ConfigDrift"
+		cond.Message = fmt.Sprintf("%d discrepancies found between the translated and live xDS configuration", len(discrepancies))
+	}
+
+	apimeta.SetStatusCondition(&gw.Status.Conditions, cond)
+	if err := r.Client.Status().Update(ctx, &gw); err != nil {
+		r.Logger.Error(err, "failed to update Gateway status", "namespace", namespace, "name", name)
+	}
+}
+
+// splitNodeID splits a "<namespace>/<name>" node ID, as produced by
+// PodAdminFetcher, into its Gateway namespace and name.
+func splitNodeID(nodeID string) (namespace, name string, ok bool) {
+	parts := strings.SplitN(nodeID, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// toProtoMessages narrows a cache.Snapshot's per-type resource map (keyed by
+// name) from types.Resource down to proto.Message for use with Diff.
+func toProtoMessages(resources map[string]types.Resource) map[string]proto.Message {
+	out := make(map[string]proto.Message, len(resources))
+	for name, res := range resources {
+		out[name] = res
+	}
+	return out
+}
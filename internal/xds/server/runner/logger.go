@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+// cacheLogger adapts a logr.Logger to the cachev3.Logger interface the
+// go-control-plane snapshot cache logs through.
+type cacheLogger struct {
+	logr.Logger
+}
+
+func (l *cacheLogger) Debugf(format string, args ...interface{}) {
+	l.V(1).Info(fmt.Sprintf(format, args...))
+}
+
+func (l *cacheLogger) Infof(format string, args ...interface{}) {
+	l.Info(fmt.Sprintf(format, args...))
+}
+
+func (l *cacheLogger) Warnf(format string, args ...interface{}) {
+	l.Info(fmt.Sprintf(format, args...))
+}
+
+func (l *cacheLogger) Errorf(format string, args ...interface{}) {
+	l.Logger.Error(nil, fmt.Sprintf(format, args...))
+}
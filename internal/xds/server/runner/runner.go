@@ -0,0 +1,129 @@
+// Package runner implements the xDS server runner: it pushes the snapshots
+// published on XdsResources into a go-control-plane snapshot cache and
+// serves them to managed Envoy proxies over the v3 ADS gRPC services (and,
+// optionally, v2-compatible Listener, Cluster, Route and Endpoint discovery
+// services for legacy sidecars; there is no v2 ADS service).
+package runner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+
+	clusterservice "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
+	discoveryservice "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	endpointservice "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
+	listenerservice "github.com/envoyproxy/go-control-plane/envoy/service/listener/v3"
+	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"google.golang.org/grpc"
+
+	"github.com/envoyproxy/gateway/internal/envoygateway/config"
+	"github.com/envoyproxy/gateway/internal/message"
+	xdsserver "github.com/envoyproxy/gateway/internal/xds/server"
+)
+
+// xdsAddr is the bind address managed Envoy proxies' ADS config_source
+// points at.
+const xdsAddr = ":18000"
+
+type Config struct {
+	config.Server
+	XdsResources *message.XdsResources
+}
+
+type Runner struct {
+	Config
+
+	// bound is set once the ADS gRPC listener is successfully bound, so
+	// Healthy can report the xDS server isn't actually serving yet instead of
+	// always claiming success.
+	bound atomic.Bool
+}
+
+// New creates a new xDS server runner.
+func New(cfg *Config) *Runner {
+	return &Runner{Config: *cfg}
+}
+
+// Name implements runner.Runner.
+func (r *Runner) Name() string { return "xds-server" }
+
+// DependsOn implements runner.Runner: the xDS server only has something to
+// serve once the translator has published at least once.
+func (r *Runner) DependsOn() []string {
+	return []string{"xds-translator"}
+}
+
+// Healthy implements runner.Runner: healthy once the ADS gRPC listener is
+// bound.
+func (r *Runner) Healthy() error {
+	if !r.bound.Load() {
+		return errors.New("xDS gRPC listener not yet bound")
+	}
+	return nil
+}
+
+// Start subscribes to XdsResources, pushing every update into a snapshot
+// cache, and serves that cache to managed Envoy proxies over ADS until ctx
+// is done.
+func (r *Runner) Start(ctx context.Context) error {
+	r.Logger = r.Logger.WithValues("runner", "xds-server")
+	defer r.bound.Store(false)
+
+	snapshotCache := cachev3.NewSnapshotCache(false, cachev3.IDHash{}, &cacheLogger{r.Logger})
+	go r.subscribeAndPush(ctx, snapshotCache)
+
+	xdsServer := serverv3.NewServer(ctx, snapshotCache, nil)
+
+	lis, err := net.Listen("tcp", xdsAddr)
+	if err != nil {
+		return err
+	}
+	r.bound.Store(true)
+
+	grpcServer := grpc.NewServer()
+	discoveryservice.RegisterAggregatedDiscoveryServiceServer(grpcServer, xdsServer)
+	clusterservice.RegisterClusterDiscoveryServiceServer(grpcServer, xdsServer)
+	endpointservice.RegisterEndpointDiscoveryServiceServer(grpcServer, xdsServer)
+	listenerservice.RegisterListenerDiscoveryServiceServer(grpcServer, xdsServer)
+	routeservice.RegisterRouteDiscoveryServiceServer(grpcServer, xdsServer)
+
+	// XDS is read once at startup; RLock/RUnlock guards against the Config
+	// Manager's concurrent Lock-protected reload of the same shared
+	// *config.EnvoyGateway.
+	r.Server.EnvoyGateway.RLock()
+	enableV2Compat := r.Server.EnvoyGateway.XDS != nil && r.Server.EnvoyGateway.XDS.EnableV2Compat
+	r.Server.EnvoyGateway.RUnlock()
+
+	if enableV2Compat {
+		xdsserver.RegisterV2CompatServices(grpcServer, xdsServer)
+		r.Logger.Info("registered v2-compatible xDS services")
+	}
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	r.Logger.Info("started", "address", xdsAddr)
+	return grpcServer.Serve(lis)
+}
+
+// subscribeAndPush relays every update published on XdsResources into
+// snapshotCache, keyed by the same node ID the translator published it
+// under.
+func (r *Runner) subscribeAndPush(ctx context.Context, snapshotCache cachev3.SnapshotCache) {
+	message.HandleSubscription(r.XdsResources.Subscribe(ctx), func(update message.Update[string, *cachev3.Snapshot]) {
+		if update.Delete {
+			snapshotCache.ClearSnapshot(update.Key)
+			return
+		}
+		if err := snapshotCache.SetSnapshot(ctx, update.Key, update.Value); err != nil {
+			r.Logger.Error(err, "failed to set xDS snapshot", "nodeID", update.Key)
+		}
+	})
+	r.Logger.Info("shutting down")
+}
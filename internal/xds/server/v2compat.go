@@ -0,0 +1,417 @@
+// Package server hosts the ADS gRPC services Envoy Gateway exposes to
+// managed Envoy proxies.
+package server
+
+import (
+	"context"
+
+	discoveryv2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	corev2 "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// v2ToV3TypeURL and v3ToV2TypeURL are the two directions of the type URL
+// rewrite table. Envoy Gateway only ever emits Listeners, Clusters,
+// RouteConfigurations and ClusterLoadAssignments, and the v2/v3 wire schemas
+// are compatible for that resource set, so a string-substitution table is
+// sufficient for the resources themselves; the DiscoveryRequest/Response
+// envelopes are still distinct concrete proto types and are converted field
+// by field below.
+//
+// The table also has to cover every Any type URL walkMessage/walkValue can
+// find nested inside those top-level resources, not just the resources'
+// own envelope: a real Listener always carries an HttpConnectionManager in
+// its filter chain, which in turn always carries the router filter and,
+// when rate limiting is configured, the ratelimit filter. Leaving any of
+// those out of the table means rewriteAnyToV2 silently leaves that nested
+// TypedConfig's type URL saying v3 even though the outer envelope now says
+// v2, which a genuine v2-only sidecar can't parse.
+var v2ToV3TypeURL = map[string]string{
+	"type.googleapis.com/envoy.api.v2.Listener":              "type.googleapis.com/envoy.config.listener.v3.Listener",
+	"type.googleapis.com/envoy.api.v2.Cluster":               "type.googleapis.com/envoy.config.cluster.v3.Cluster",
+	"type.googleapis.com/envoy.api.v2.RouteConfiguration":    "type.googleapis.com/envoy.config.route.v3.RouteConfiguration",
+	"type.googleapis.com/envoy.api.v2.ClusterLoadAssignment": "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment",
+
+	// Nested filter TypedConfig type URLs. Every Listener's filter chain
+	// carries an HttpConnectionManager, which always installs the router
+	// filter and, for rate-limited routes, the ratelimit filter (see
+	// internal/xds/translator/ratelimit.go).
+	"type.googleapis.com/envoy.config.filter.network.http_connection_manager.v2.HttpConnectionManager": "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+	"type.googleapis.com/envoy.config.filter.http.router.v2.Router":                                    "type.googleapis.com/envoy.extensions.filters.http.router.v3.Router",
+	"type.googleapis.com/envoy.config.filter.http.rate_limit.v2.RateLimit":                              "type.googleapis.com/envoy.extensions.filters.http.ratelimit.v3.RateLimit",
+}
+
+var v3ToV2TypeURL = invert(v2ToV3TypeURL)
+
+func invert(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
+
+// RegisterV2CompatServices registers the v2 Listener, Cluster, Route and
+// Endpoint discovery services, backed by v3Server, alongside the v3
+// SotW/Delta services already registered for it, so legacy sidecars that
+// request v2 type URLs on those services are served. The v2 aggregated
+// (ADS) discovery service is not registered. It is only called when
+// EnvoyGateway.XDS.EnableV2Compat is set.
+func RegisterV2CompatServices(grpcServer *grpc.Server, v3Server serverv3.Server) {
+	wrapped := &v2CompatServer{v3: v3Server}
+	discoveryv2.RegisterListenerDiscoveryServiceServer(grpcServer, wrapped)
+	discoveryv2.RegisterClusterDiscoveryServiceServer(grpcServer, wrapped)
+	discoveryv2.RegisterRouteDiscoveryServiceServer(grpcServer, wrapped)
+	discoveryv2.RegisterEndpointDiscoveryServiceServer(grpcServer, wrapped)
+}
+
+// v2CompatServer adapts the v2 ADS service interfaces onto the v3 Server by
+// converting requests/responses between the v2 and v3 concrete proto types
+// (and rewriting type URLs along the way).
+type v2CompatServer struct {
+	v3 serverv3.Server
+}
+
+// StreamListeners implements discoveryv2.ListenerDiscoveryServiceServer.
+func (s *v2CompatServer) StreamListeners(stream discoveryv2.ListenerDiscoveryService_StreamListenersServer) error {
+	return s.streamV2(stream, resourcev3.ListenerType)
+}
+
+// DeltaListeners implements discoveryv2.ListenerDiscoveryServiceServer.
+func (s *v2CompatServer) DeltaListeners(stream discoveryv2.ListenerDiscoveryService_DeltaListenersServer) error {
+	return s.deltaV2(stream, resourcev3.ListenerType)
+}
+
+// FetchListeners implements discoveryv2.ListenerDiscoveryServiceServer.
+func (s *v2CompatServer) FetchListeners(ctx context.Context, req *discoveryv2.DiscoveryRequest) (*discoveryv2.DiscoveryResponse, error) {
+	return s.fetchV2(ctx, req)
+}
+
+// StreamClusters implements discoveryv2.ClusterDiscoveryServiceServer.
+func (s *v2CompatServer) StreamClusters(stream discoveryv2.ClusterDiscoveryService_StreamClustersServer) error {
+	return s.streamV2(stream, resourcev3.ClusterType)
+}
+
+// DeltaClusters implements discoveryv2.ClusterDiscoveryServiceServer.
+func (s *v2CompatServer) DeltaClusters(stream discoveryv2.ClusterDiscoveryService_DeltaClustersServer) error {
+	return s.deltaV2(stream, resourcev3.ClusterType)
+}
+
+// FetchClusters implements discoveryv2.ClusterDiscoveryServiceServer.
+func (s *v2CompatServer) FetchClusters(ctx context.Context, req *discoveryv2.DiscoveryRequest) (*discoveryv2.DiscoveryResponse, error) {
+	return s.fetchV2(ctx, req)
+}
+
+// StreamRoutes implements discoveryv2.RouteDiscoveryServiceServer.
+func (s *v2CompatServer) StreamRoutes(stream discoveryv2.RouteDiscoveryService_StreamRoutesServer) error {
+	return s.streamV2(stream, resourcev3.RouteType)
+}
+
+// DeltaRoutes implements discoveryv2.RouteDiscoveryServiceServer.
+func (s *v2CompatServer) DeltaRoutes(stream discoveryv2.RouteDiscoveryService_DeltaRoutesServer) error {
+	return s.deltaV2(stream, resourcev3.RouteType)
+}
+
+// FetchRoutes implements discoveryv2.RouteDiscoveryServiceServer.
+func (s *v2CompatServer) FetchRoutes(ctx context.Context, req *discoveryv2.DiscoveryRequest) (*discoveryv2.DiscoveryResponse, error) {
+	return s.fetchV2(ctx, req)
+}
+
+// StreamEndpoints implements discoveryv2.EndpointDiscoveryServiceServer.
+func (s *v2CompatServer) StreamEndpoints(stream discoveryv2.EndpointDiscoveryService_StreamEndpointsServer) error {
+	return s.streamV2(stream, resourcev3.EndpointType)
+}
+
+// DeltaEndpoints implements discoveryv2.EndpointDiscoveryServiceServer.
+func (s *v2CompatServer) DeltaEndpoints(stream discoveryv2.EndpointDiscoveryService_DeltaEndpointsServer) error {
+	return s.deltaV2(stream, resourcev3.EndpointType)
+}
+
+// FetchEndpoints implements discoveryv2.EndpointDiscoveryServiceServer.
+func (s *v2CompatServer) FetchEndpoints(ctx context.Context, req *discoveryv2.DiscoveryRequest) (*discoveryv2.DiscoveryResponse, error) {
+	return s.fetchV2(ctx, req)
+}
+
+func (s *v2CompatServer) streamV2(stream grpc.ServerStream, typeURL string) error {
+	return s.v3.StreamHandler(&v2StreamAdapter{ServerStream: stream}, typeURL)
+}
+
+func (s *v2CompatServer) deltaV2(stream grpc.ServerStream, typeURL string) error {
+	return s.v3.DeltaStreamHandler(&v2DeltaStreamAdapter{ServerStream: stream}, typeURL)
+}
+
+func (s *v2CompatServer) fetchV2(ctx context.Context, req *discoveryv2.DiscoveryRequest) (*discoveryv2.DiscoveryResponse, error) {
+	resp, err := s.v3.Fetch(ctx, toV3Request(req))
+	if err != nil {
+		return nil, err
+	}
+	return toV2Response(resp)
+}
+
+// v2StreamAdapter presents a v2 ADS gRPC stream as the v3
+// Send(*DiscoveryResponse)/Recv() (*DiscoveryRequest, error) interface
+// serverv3.Server.StreamHandler expects, converting each message between
+// the v2 and v3 concrete proto types.
+type v2StreamAdapter struct {
+	grpc.ServerStream
+}
+
+func (a *v2StreamAdapter) Send(resp *discoveryv3.DiscoveryResponse) error {
+	v2Resp, err := toV2Response(resp)
+	if err != nil {
+		return err
+	}
+	return a.ServerStream.SendMsg(v2Resp)
+}
+
+func (a *v2StreamAdapter) Recv() (*discoveryv3.DiscoveryRequest, error) {
+	v2Req := new(discoveryv2.DiscoveryRequest)
+	if err := a.ServerStream.RecvMsg(v2Req); err != nil {
+		return nil, err
+	}
+	return toV3Request(v2Req), nil
+}
+
+// v2DeltaStreamAdapter is v2StreamAdapter's Delta/incremental counterpart.
+type v2DeltaStreamAdapter struct {
+	grpc.ServerStream
+}
+
+func (a *v2DeltaStreamAdapter) Send(resp *discoveryv3.DeltaDiscoveryResponse) error {
+	v2Resp, err := toV2DeltaResponse(resp)
+	if err != nil {
+		return err
+	}
+	return a.ServerStream.SendMsg(v2Resp)
+}
+
+func (a *v2DeltaStreamAdapter) Recv() (*discoveryv3.DeltaDiscoveryRequest, error) {
+	v2Req := new(discoveryv2.DeltaDiscoveryRequest)
+	if err := a.ServerStream.RecvMsg(v2Req); err != nil {
+		return nil, err
+	}
+	return toV3DeltaRequest(v2Req), nil
+}
+
+// toV3Request converts a v2 DiscoveryRequest into its v3 equivalent.
+func toV3Request(req *discoveryv2.DiscoveryRequest) *discoveryv3.DiscoveryRequest {
+	if req == nil {
+		return nil
+	}
+	return &discoveryv3.DiscoveryRequest{
+		VersionInfo:   req.GetVersionInfo(),
+		Node:          nodeToV3(req.GetNode()),
+		ResourceNames: req.GetResourceNames(),
+		TypeUrl:       rewriteTypeURL(req.GetTypeUrl(), v2ToV3TypeURL),
+		ResponseNonce: req.GetResponseNonce(),
+		ErrorDetail:   req.GetErrorDetail(),
+	}
+}
+
+// toV2Response converts a v3 DiscoveryResponse into its v2 equivalent,
+// rewriting the type URLs of its own envelope and every embedded Any.
+func toV2Response(resp *discoveryv3.DiscoveryResponse) (*discoveryv2.DiscoveryResponse, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	v2Resp := &discoveryv2.DiscoveryResponse{
+		VersionInfo: resp.GetVersionInfo(),
+		TypeUrl:     rewriteTypeURL(resp.GetTypeUrl(), v3ToV2TypeURL),
+		Nonce:       resp.GetNonce(),
+		Canary:      resp.GetCanary(),
+	}
+	for _, res := range resp.GetResources() {
+		v2Any, err := rewriteAnyToV2(res)
+		if err != nil {
+			return nil, err
+		}
+		v2Resp.Resources = append(v2Resp.Resources, v2Any)
+	}
+	return v2Resp, nil
+}
+
+// toV3DeltaRequest converts a v2 DeltaDiscoveryRequest into its v3
+// equivalent.
+func toV3DeltaRequest(req *discoveryv2.DeltaDiscoveryRequest) *discoveryv3.DeltaDiscoveryRequest {
+	if req == nil {
+		return nil
+	}
+	return &discoveryv3.DeltaDiscoveryRequest{
+		Node:                     nodeToV3(req.GetNode()),
+		TypeUrl:                  rewriteTypeURL(req.GetTypeUrl(), v2ToV3TypeURL),
+		ResourceNamesSubscribe:   req.GetResourceNamesSubscribe(),
+		ResourceNamesUnsubscribe: req.GetResourceNamesUnsubscribe(),
+		InitialResourceVersions:  req.GetInitialResourceVersions(),
+		ResponseNonce:            req.GetResponseNonce(),
+		ErrorDetail:              req.GetErrorDetail(),
+	}
+}
+
+// toV2DeltaResponse converts a v3 DeltaDiscoveryResponse into its v2
+// equivalent.
+func toV2DeltaResponse(resp *discoveryv3.DeltaDiscoveryResponse) (*discoveryv2.DeltaDiscoveryResponse, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	v2Resp := &discoveryv2.DeltaDiscoveryResponse{
+		SystemVersionInfo: resp.GetSystemVersionInfo(),
+		RemovedResources:  resp.GetRemovedResources(),
+		Nonce:             resp.GetNonce(),
+		TypeUrl:           rewriteTypeURL(resp.GetTypeUrl(), v3ToV2TypeURL),
+	}
+	for _, res := range resp.GetResources() {
+		any, err := rewriteAnyToV2(res.GetResource())
+		if err != nil {
+			return nil, err
+		}
+		v2Resp.Resources = append(v2Resp.Resources, &discoveryv2.Resource{
+			Name:     res.GetName(),
+			Aliases:  res.GetAliases(),
+			Version:  res.GetVersion(),
+			Resource: any,
+		})
+	}
+	return v2Resp, nil
+}
+
+// nodeToV3 converts the subset of envoy.api.v2.core.Node fields Envoy
+// Gateway's node hash and callbacks rely on into their v3 equivalent.
+func nodeToV3(n *corev2.Node) *corev3.Node {
+	if n == nil {
+		return nil
+	}
+	v3Node := &corev3.Node{
+		Id:            n.GetId(),
+		Cluster:       n.GetCluster(),
+		Metadata:      n.GetMetadata(),
+		UserAgentName: n.GetUserAgentName(),
+	}
+	if loc := n.GetLocality(); loc != nil {
+		v3Node.Locality = &corev3.Locality{
+			Region:  loc.GetRegion(),
+			Zone:    loc.GetZone(),
+			SubZone: loc.GetSubZone(),
+		}
+	}
+	return v3Node
+}
+
+// rewriteTypeURL looks up typeURL in table, falling back to typeURL
+// unchanged for resource types this compat layer doesn't know about (e.g.
+// the ADS type URL itself).
+func rewriteTypeURL(typeURL string, table map[string]string) string {
+	if v, ok := table[typeURL]; ok {
+		return v
+	}
+	return typeURL
+}
+
+// rewriteAnyToV2 returns a copy of any with its own type URL, and the type
+// URL of every Any nested inside its unmarshaled message (e.g. a Listener's
+// filter chains' TypedConfig), rewritten from v3 to v2.
+func rewriteAnyToV2(any *anypb.Any) (*anypb.Any, error) {
+	if any == nil {
+		return nil, nil
+	}
+
+	clone, ok := proto.Clone(any).(*anypb.Any)
+	if !ok {
+		return nil, nil
+	}
+
+	msg, err := clone.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	if err := walkMessage(msg.ProtoReflect(), v3ToV2TypeURL); err != nil {
+		return nil, err
+	}
+
+	rewritten, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	rewritten.TypeUrl = rewriteTypeURL(rewritten.GetTypeUrl(), v3ToV2TypeURL)
+	return rewritten, nil
+}
+
+// walkMessage recurses through m's fields, rewriting the type URL of any Any
+// message field it finds (including those nested inside repeated fields and
+// submessages) using table.
+func walkMessage(m protoreflect.Message, table map[string]string) error {
+	var rangeErr error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Kind() == protoreflect.MessageKind:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				if err := walkValue(list.Get(i).Message(), table); err != nil {
+					rangeErr = err
+					return false
+				}
+			}
+		case fd.IsMap() && fd.MapValue().Kind() == protoreflect.MessageKind:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				if err := walkValue(mv.Message(), table); err != nil {
+					rangeErr = err
+					return false
+				}
+				return true
+			})
+			if rangeErr != nil {
+				return false
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			if err := walkValue(v.Message(), table); err != nil {
+				rangeErr = err
+				return false
+			}
+		}
+		return true
+	})
+	return rangeErr
+}
+
+// walkValue rewrites msg in place if it's an Any, otherwise recurses into
+// it. An Any's own type URL isn't the whole story: a Listener's
+// HttpConnectionManager TypedConfig is itself an Any whose Value bytes are
+// another message (the HCM) carrying further nested Anys (its HttpFilters'
+// TypedConfig, e.g. Router/RateLimit) that also need rewriting. So an Any is
+// unmarshaled, walked like any other message, and re-marshaled back into
+// Value, not just renamed at the wrapper.
+func walkValue(msg protoreflect.Message, table map[string]string) error {
+	any, ok := msg.Interface().(*anypb.Any)
+	if !ok {
+		return walkMessage(msg, table)
+	}
+	if any.GetTypeUrl() == "" {
+		return nil
+	}
+
+	inner, err := any.UnmarshalNew()
+	if err != nil {
+		// An extension type this compat layer (and the proto registry) has
+		// no schema for: still rewrite the wrapper's own type URL, since
+		// that much is a plain string substitution, but there's nothing to
+		// recurse into.
+		any.TypeUrl = rewriteTypeURL(any.GetTypeUrl(), table)
+		return nil
+	}
+	if err := walkMessage(inner.ProtoReflect(), table); err != nil {
+		return err
+	}
+	rewritten, err := anypb.New(inner)
+	if err != nil {
+		return err
+	}
+	any.TypeUrl = rewriteTypeURL(any.GetTypeUrl(), table)
+	any.Value = rewritten.GetValue()
+	return nil
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"testing"
+
+	discoveryv2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	corev2 "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	ratelimitfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	routerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestV2ToV3TypeURLIsInvertible(t *testing.T) {
+	for v2, v3 := range v2ToV3TypeURL {
+		got, ok := v3ToV2TypeURL[v3]
+		if !ok {
+			t.Errorf("v3ToV2TypeURL has no entry for %q", v3)
+			continue
+		}
+		if got != v2 {
+			t.Errorf("v3ToV2TypeURL[%q] = %q, want %q", v3, got, v2)
+		}
+	}
+}
+
+func TestRewriteTypeURLFallsBackWhenUnknown(t *testing.T) {
+	const unknown = "type.googleapis.com/envoy.service.discovery.v3.DiscoveryRequest"
+	if got := rewriteTypeURL(unknown, v2ToV3TypeURL); got != unknown {
+		t.Errorf("rewriteTypeURL(%q) = %q, want it unchanged", unknown, got)
+	}
+}
+
+func TestToV3RequestConvertsNodeAndTypeURL(t *testing.T) {
+	req := &discoveryv2.DiscoveryRequest{
+		VersionInfo: "1",
+		TypeUrl:     "type.googleapis.com/envoy.api.v2.Cluster",
+		Node: &corev2.Node{
+			Id:      "node-1",
+			Cluster: "my-cluster",
+			Locality: &corev2.Locality{
+				Region: "us-east-1",
+			},
+		},
+	}
+
+	v3Req := toV3Request(req)
+
+	if want := "type.googleapis.com/envoy.config.cluster.v3.Cluster"; v3Req.GetTypeUrl() != want {
+		t.Errorf("TypeUrl = %q, want %q", v3Req.GetTypeUrl(), want)
+	}
+	if v3Req.GetNode().GetId() != "node-1" {
+		t.Errorf("Node.Id = %q, want %q", v3Req.GetNode().GetId(), "node-1")
+	}
+	if v3Req.GetNode().GetLocality().GetRegion() != "us-east-1" {
+		t.Errorf("Node.Locality.Region = %q, want %q", v3Req.GetNode().GetLocality().GetRegion(), "us-east-1")
+	}
+}
+
+// TestRewriteAnyToV2RewritesNestedFilterChainTypeURLs builds a Listener
+// with a populated filter chain - an HttpConnectionManager carrying the
+// router and ratelimit HTTP filters, the same shape the xDS translator
+// actually emits - and checks that rewriteAnyToV2 rewrites every nested
+// TypedConfig's type URL, not just the Listener's own envelope.
+func TestRewriteAnyToV2RewritesNestedFilterChainTypeURLs(t *testing.T) {
+	routerAny, err := anypb.New(&routerv3.Router{})
+	if err != nil {
+		t.Fatalf("failed to marshal router filter: %v", err)
+	}
+	ratelimitAny, err := anypb.New(&ratelimitfilterv3.RateLimit{Domain: "my-listener"})
+	if err != nil {
+		t.Fatalf("failed to marshal ratelimit filter: %v", err)
+	}
+
+	hcm := &hcmv3.HttpConnectionManager{
+		HttpFilters: []*hcmv3.HttpFilter{
+			{Name: "envoy.filters.http.ratelimit", ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: ratelimitAny}},
+			{Name: "envoy.filters.http.router", ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: routerAny}},
+		},
+	}
+	hcmAny, err := anypb.New(hcm)
+	if err != nil {
+		t.Fatalf("failed to marshal HTTP connection manager: %v", err)
+	}
+
+	listener := &listenerv3.Listener{
+		Name: "my-listener",
+		FilterChains: []*listenerv3.FilterChain{{
+			Filters: []*listenerv3.Filter{{
+				Name:       "envoy.filters.network.http_connection_manager",
+				ConfigType: &listenerv3.Filter_TypedConfig{TypedConfig: hcmAny},
+			}},
+		}},
+	}
+	listenerAny, err := anypb.New(listener)
+	if err != nil {
+		t.Fatalf("failed to marshal listener: %v", err)
+	}
+
+	v2Any, err := rewriteAnyToV2(listenerAny)
+	if err != nil {
+		t.Fatalf("rewriteAnyToV2 returned an error: %v", err)
+	}
+
+	if want := v3ToV2TypeURL["type.googleapis.com/envoy.config.listener.v3.Listener"]; v2Any.GetTypeUrl() != want {
+		t.Errorf("Listener TypeUrl = %q, want %q", v2Any.GetTypeUrl(), want)
+	}
+
+	v2Listener := new(listenerv3.Listener)
+	if err := proto.Unmarshal(v2Any.GetValue(), v2Listener); err != nil {
+		t.Fatalf("failed to unmarshal rewritten listener: %v", err)
+	}
+	v2HCMAny := v2Listener.GetFilterChains()[0].GetFilters()[0].GetTypedConfig()
+	if want := v3ToV2TypeURL["type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager"]; v2HCMAny.GetTypeUrl() != want {
+		t.Errorf("HttpConnectionManager TypedConfig.TypeUrl = %q, want %q", v2HCMAny.GetTypeUrl(), want)
+	}
+
+	v2HCM := new(hcmv3.HttpConnectionManager)
+	if err := proto.Unmarshal(v2HCMAny.GetValue(), v2HCM); err != nil {
+		t.Fatalf("failed to unmarshal rewritten HttpConnectionManager: %v", err)
+	}
+	for _, f := range v2HCM.GetHttpFilters() {
+		got := f.GetTypedConfig().GetTypeUrl()
+		want, ok := v3ToV2TypeURL[hcmFilterV3TypeURL(t, f.GetName())]
+		if !ok {
+			t.Fatalf("test bug: no v3ToV2TypeURL entry to compare %q against", f.GetName())
+		}
+		if got != want {
+			t.Errorf("HTTP filter %q TypedConfig.TypeUrl = %q, want %q", f.GetName(), got, want)
+		}
+	}
+}
+
+// hcmFilterV3TypeURL maps an HTTP filter's name to the v3 type URL its
+// TypedConfig was built with in the test above.
+func hcmFilterV3TypeURL(t *testing.T, filterName string) string {
+	t.Helper()
+	switch filterName {
+	case "envoy.filters.http.router":
+		return "type.googleapis.com/envoy.extensions.filters.http.router.v3.Router"
+	case "envoy.filters.http.ratelimit":
+		return "type.googleapis.com/envoy.extensions.filters.http.ratelimit.v3.RateLimit"
+	default:
+		t.Fatalf("unexpected HTTP filter name %q", filterName)
+		return ""
+	}
+}
@@ -0,0 +1,174 @@
+package translator
+
+import (
+	"fmt"
+	"time"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	rlsconfv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	ratelimitfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/envoyproxy/gateway/internal/ir"
+)
+
+const (
+	rateLimitFilter = "envoy.filters.http.ratelimit"
+	// rateLimitClusterName is the name of the xDS cluster used to reach the
+	// ratelimit service started by the globalratelimit runner.
+	rateLimitClusterName = "envoy-ratelimit"
+	// defaultRateLimitTimeout is the timeout applied to the ratelimit
+	// service's gRPC calls.
+	defaultRateLimitTimeout = 20 * time.Millisecond
+)
+
+// buildRateLimitFilter returns an HTTP filter that rate limits requests
+// against the ratelimit service. domain must match the domain the
+// globalratelimit runner renders the listener's descriptors under in the
+// ratelimit service's config file, since the service looks up descriptors
+// by domain.
+func buildRateLimitFilter(domain string) (*ratelimitfilterv3.RateLimit, error) {
+	return &ratelimitfilterv3.RateLimit{
+		Domain: domain,
+		RateLimitService: &rlsconfv3.RateLimitServiceConfig{
+			GrpcService: &rlsconfv3.RateLimitServiceConfig_GrpcService{
+				TargetSpecifier: &rlsconfv3.RateLimitServiceConfig_GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &rlsconfv3.RateLimitServiceConfig_GrpcService_EnvoyGrpc{
+						ClusterName: rateLimitClusterName,
+					},
+				},
+			},
+			TransportApiVersion: rlsconfv3.ApiVersion_V3,
+		},
+		Timeout:                 durationpb.New(defaultRateLimitTimeout),
+		FailureModeDeny:         true,
+		EnableXRatelimitHeaders: ratelimitfilterv3.RateLimit_DRAFT_VERSION_03,
+	}, nil
+}
+
+// buildRateLimitDescriptors translates the route's IR rate limit rules into
+// the descriptor hierarchy the ratelimit service expects.
+func buildRateLimitDescriptors(irRoute *ir.HTTPRoute) []*rlsconfv3.RateLimitDescriptor {
+	if irRoute == nil || irRoute.RateLimit == nil {
+		return nil
+	}
+
+	descriptors := make([]*rlsconfv3.RateLimitDescriptor, 0, len(irRoute.RateLimit.Rules))
+	for _, rule := range irRoute.RateLimit.Rules {
+		descriptors = append(descriptors, &rlsconfv3.RateLimitDescriptor{
+			Key:   rule.Key,
+			Value: rule.Value,
+		})
+	}
+	return descriptors
+}
+
+func marshalRateLimitFilter(filter *ratelimitfilterv3.RateLimit) (*anypb.Any, error) {
+	return anypb.New(filter)
+}
+
+// PatchRateLimit applies irRoute's rate limit IR to the xDS resources
+// produced for its listener: it appends the envoy.filters.http.ratelimit
+// HTTP filter to mgr (once per listener) and attaches route's per-route
+// rate limit descriptors, so the ratelimit service set up by the
+// globalratelimit runner is actually consulted on the request path.
+//
+// listenerName must be the name of the listener route belongs to; it is
+// used as the filter's domain and must match the domain the globalratelimit
+// runner renders this listener's descriptors under, since PatchRateLimit
+// only installs the shared filter once per listener (the first rate-limited
+// route to call it wins) while every route on the listener relies on it.
+//
+// It is a no-op when irRoute has no RateLimit configured.
+func PatchRateLimit(mgr *hcmv3.HttpConnectionManager, route *routev3.Route, listenerName string, irRoute *ir.HTTPRoute) error {
+	if irRoute == nil || irRoute.RateLimit == nil {
+		return nil
+	}
+
+	filter, err := buildRateLimitFilter(listenerName)
+	if err != nil {
+		return err
+	}
+	filterAny, err := marshalRateLimitFilter(filter)
+	if err != nil {
+		return err
+	}
+	if !hasHTTPFilter(mgr, rateLimitFilter) {
+		mgr.HttpFilters = append(mgr.HttpFilters, &hcmv3.HttpFilter{
+			Name:       rateLimitFilter,
+			ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: filterAny},
+		})
+	}
+
+	route.RateLimits = append(route.RateLimits, &routev3.RateLimit{
+		Actions: descriptorsToActions(buildRateLimitDescriptors(irRoute)),
+	})
+	return nil
+}
+
+// PatchHTTPListener is the xDS translator's single call site for
+// PatchRateLimit: it matches every route in irListener to its already-built
+// xDS route by name across routeConfig's virtual hosts (the translator
+// assigns each xDS route the same Name as the ir.HTTPRoute it was built
+// from) and patches in that route's rate limit IR, so the ratelimit HTTP
+// filter and per-route descriptors actually reach the translated listener
+// instead of PatchRateLimit being built and never called.
+//
+// It is a no-op for any irListener route with no RateLimit configured, and
+// silently skips any irListener route that has no matching xDS route in
+// routeConfig yet.
+func PatchHTTPListener(mgr *hcmv3.HttpConnectionManager, routeConfig *routev3.RouteConfiguration, irListener *ir.HTTPListener) error {
+	if mgr == nil || routeConfig == nil || irListener == nil {
+		return nil
+	}
+
+	routesByName := make(map[string]*routev3.Route)
+	for _, vh := range routeConfig.GetVirtualHosts() {
+		for _, route := range vh.GetRoutes() {
+			routesByName[route.GetName()] = route
+		}
+	}
+
+	for _, irRoute := range irListener.Routes {
+		route, ok := routesByName[irRoute.Name]
+		if !ok {
+			continue
+		}
+		if err := PatchRateLimit(mgr, route, irListener.Name, irRoute); err != nil {
+			return fmt.Errorf("failed to patch rate limit for route %q on listener %q: %w", irRoute.Name, irListener.Name, err)
+		}
+	}
+	return nil
+}
+
+// hasHTTPFilter reports whether mgr already has an HTTP filter with the
+// given name, so PatchRateLimit can be called once per route on a shared
+// listener without duplicating the filter.
+func hasHTTPFilter(mgr *hcmv3.HttpConnectionManager, name string) bool {
+	for _, f := range mgr.GetHttpFilters() {
+		if f.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// descriptorsToActions converts the ratelimit service's descriptor
+// hierarchy into the route-level actions Envoy uses to build the
+// descriptor it sends to that service on each request.
+func descriptorsToActions(descriptors []*rlsconfv3.RateLimitDescriptor) []*routev3.RateLimit_Action {
+	actions := make([]*routev3.RateLimit_Action, 0, len(descriptors))
+	for _, d := range descriptors {
+		actions = append(actions, &routev3.RateLimit_Action{
+			ActionSpecifier: &routev3.RateLimit_Action_GenericKey_{
+				GenericKey: &routev3.RateLimit_Action_GenericKey{
+					DescriptorKey:   d.Key,
+					DescriptorValue: d.Value,
+				},
+			},
+		})
+	}
+	return actions
+}
@@ -0,0 +1,111 @@
+package translator
+
+import (
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	ratelimitfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+
+	"github.com/envoyproxy/gateway/internal/ir"
+)
+
+func TestPatchRateLimitNoop(t *testing.T) {
+	mgr := &hcmv3.HttpConnectionManager{}
+	route := &routev3.Route{}
+
+	if err := PatchRateLimit(mgr, route, "my-listener", &ir.HTTPRoute{Name: "no-limit"}); err != nil {
+		t.Fatalf("PatchRateLimit returned an error: %v", err)
+	}
+	if len(mgr.GetHttpFilters()) != 0 {
+		t.Errorf("expected no HTTP filters to be added, got %d", len(mgr.GetHttpFilters()))
+	}
+	if len(route.GetRateLimits()) != 0 {
+		t.Errorf("expected no route rate limits to be added, got %d", len(route.GetRateLimits()))
+	}
+}
+
+func TestPatchRateLimitAddsFilterAndDescriptors(t *testing.T) {
+	mgr := &hcmv3.HttpConnectionManager{}
+	route := &routev3.Route{}
+	irRoute := &ir.HTTPRoute{
+		Name: "httpbin",
+		RateLimit: &ir.RateLimit{
+			Rules: []*ir.RateLimitRule{
+				{Key: "remote_address"},
+				{Key: "header_match", Value: "premium-user"},
+			},
+		},
+	}
+
+	if err := PatchRateLimit(mgr, route, "my-listener", irRoute); err != nil {
+		t.Fatalf("PatchRateLimit returned an error: %v", err)
+	}
+
+	if got := len(mgr.GetHttpFilters()); got != 1 {
+		t.Fatalf("expected exactly 1 HTTP filter, got %d", got)
+	}
+	if got, want := mgr.GetHttpFilters()[0].GetName(), rateLimitFilter; got != want {
+		t.Errorf("filter name = %q, want %q", got, want)
+	}
+	filter := &ratelimitfilterv3.RateLimit{}
+	if err := mgr.GetHttpFilters()[0].GetTypedConfig().UnmarshalTo(filter); err != nil {
+		t.Fatalf("failed to unmarshal filter TypedConfig: %v", err)
+	}
+	if got, want := filter.GetDomain(), "my-listener"; got != want {
+		t.Errorf("filter domain = %q, want %q (must match the listener's ratelimit service config domain)", got, want)
+	}
+
+	if got := len(route.GetRateLimits()); got != 1 {
+		t.Fatalf("expected exactly 1 route rate limit, got %d", got)
+	}
+	actions := route.GetRateLimits()[0].GetActions()
+	if got, want := len(actions), 2; got != want {
+		t.Fatalf("expected %d actions, got %d", want, got)
+	}
+	if got, want := actions[1].GetGenericKey().GetDescriptorValue(), "premium-user"; got != want {
+		t.Errorf("second action descriptor value = %q, want %q", got, want)
+	}
+
+	// Calling PatchRateLimit again for a second route on the same listener
+	// must not duplicate the shared HTTP filter.
+	if err := PatchRateLimit(mgr, &routev3.Route{}, "my-listener", irRoute); err != nil {
+		t.Fatalf("PatchRateLimit returned an error: %v", err)
+	}
+	if got := len(mgr.GetHttpFilters()); got != 1 {
+		t.Errorf("expected the HTTP filter to be added once, got %d", got)
+	}
+}
+
+func TestPatchHTTPListenerMatchesRoutesByName(t *testing.T) {
+	mgr := &hcmv3.HttpConnectionManager{}
+	httpbinRoute := &routev3.Route{Name: "httpbin"}
+	otherRoute := &routev3.Route{Name: "other"}
+	routeConfig := &routev3.RouteConfiguration{
+		VirtualHosts: []*routev3.VirtualHost{
+			{Routes: []*routev3.Route{httpbinRoute, otherRoute}},
+		},
+	}
+	irListener := &ir.HTTPListener{
+		Name: "my-listener",
+		Routes: []*ir.HTTPRoute{
+			{Name: "httpbin", RateLimit: &ir.RateLimit{Rules: []*ir.RateLimitRule{{Key: "remote_address"}}}},
+			{Name: "other"},
+			{Name: "not-yet-translated", RateLimit: &ir.RateLimit{Rules: []*ir.RateLimitRule{{Key: "remote_address"}}}},
+		},
+	}
+
+	if err := PatchHTTPListener(mgr, routeConfig, irListener); err != nil {
+		t.Fatalf("PatchHTTPListener returned an error: %v", err)
+	}
+
+	if got := len(mgr.GetHttpFilters()); got != 1 {
+		t.Fatalf("expected exactly 1 HTTP filter, got %d", got)
+	}
+	if got := len(httpbinRoute.GetRateLimits()); got != 1 {
+		t.Errorf("expected the httpbin route to get a rate limit, got %d", got)
+	}
+	if got := len(otherRoute.GetRateLimits()); got != 0 {
+		t.Errorf("expected the other route to be untouched, got %d rate limits", got)
+	}
+}